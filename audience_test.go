@@ -0,0 +1,83 @@
+package uaa_go_client_test
+
+import (
+	"crypto/rsa"
+
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"github.com/golang-jwt/jwt/v4"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Audience validation", func() {
+	var (
+		client     uaa_go_client.Client
+		privateKey *rsa.PrivateKey
+	)
+
+	makeTokenWithAudience := func(audience interface{}) string {
+		claims := tokenClaims()
+		if audience != nil {
+			claims["aud"] = audience
+		}
+		return "bearer " + signClaims(jwt.SigningMethodRS256, privateKey, "some-key-id", claims)
+	}
+
+	BeforeEach(func() {
+		startUaaServer()
+
+		var (
+			publicKey *rsa.PublicKey
+			err       error
+		)
+		privateKey, publicKey, err = generateRSAKeyPair()
+		Expect(err).NotTo(HaveOccurred())
+
+		server.AppendHandlers(
+			getJSONWebKeySetFetchHandler(rsaVerificationKey("some-key-id", publicKey)),
+			getIssuerFetchHandler(),
+		)
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("when no audiences are configured", func() {
+		It("accepts tokens for any audience", func() {
+			Expect(client.DecodeToken(makeTokenWithAudience([]string{"other-resource"}), "some.scope")).To(Succeed())
+		})
+	})
+
+	Context("when audiences are configured", func() {
+		BeforeEach(func() {
+			cfg.Audiences = []string{"routing", "some"}
+		})
+
+		It("accepts tokens whose audience list includes a configured audience", func() {
+			Expect(client.DecodeToken(makeTokenWithAudience([]string{"cloud_controller", "some"}), "some.scope")).To(Succeed())
+		})
+
+		It("accepts tokens with a single string audience", func() {
+			Expect(client.DecodeToken(makeTokenWithAudience("routing"), "some.scope")).To(Succeed())
+		})
+
+		It("rejects tokens minted for a different resource server", func() {
+			err := client.DecodeToken(makeTokenWithAudience([]string{"cloud_controller"}), "some.scope")
+			Expect(err).To(Equal(uaa_go_client.ErrInvalidAudience))
+		})
+
+		It("rejects tokens without an audience", func() {
+			err := client.DecodeToken(makeTokenWithAudience(nil), "some.scope")
+			Expect(err).To(Equal(uaa_go_client.ErrInvalidAudience))
+		})
+	})
+})
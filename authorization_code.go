@@ -0,0 +1,121 @@
+package uaa_go_client
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+
+	"code.cloudfoundry.org/lager"
+
+	"code.cloudfoundry.org/uaa-go-client/schema"
+)
+
+const codeChallengeMethodS256 = "S256"
+
+func (u *UaaClient) AuthorizationCodeURL(redirectURI string, opts ...TokenOption) (*schema.AuthorizationRequest, error) {
+	if u.config.ClientName == "" {
+		return nil, errors.New("OAuth Client ID cannot be empty")
+	}
+
+	if redirectURI == "" {
+		return nil, errors.New("Redirect URI cannot be empty")
+	}
+
+	state, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+
+	codeVerifier, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+
+	values := url.Values{}
+	values.Add("client_id", u.config.ClientName)
+	values.Add("response_type", "code")
+	values.Add("redirect_uri", redirectURI)
+	values.Add("state", state)
+	values.Add("nonce", nonce)
+	values.Add("code_challenge", codeChallenge(codeVerifier))
+	values.Add("code_challenge_method", codeChallengeMethodS256)
+	for _, opt := range opts {
+		opt(values)
+	}
+
+	return &schema.AuthorizationRequest{
+		URL:          u.authorizeURL(u.logger.Session("uaa-client")) + "?" + values.Encode(),
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+	}, nil
+}
+
+func (u *UaaClient) ExchangeAuthorizationCode(code, codeVerifier, redirectURI string) (*schema.Token, error) {
+	logger := u.logger.Session("uaa-client")
+	tokenURL := u.tokenURL(logger)
+	logger.Debug("started-exchanging-authorization-code", lager.Data{"endpoint": tokenURL})
+
+	// Public clients, such as the cf CLI, have no secret and identify
+	// themselves with client_id alone; the PKCE verifier binds the code to
+	// them instead.
+	newRequest := u.newClientAuthenticatedRequest
+	if u.isPublicClient() {
+		if u.config.ClientName == "" {
+			return nil, errors.New("OAuth Client ID cannot be empty")
+		}
+		newRequest = u.newPublicClientRequest
+	} else if err := u.config.CheckCredentials(); err != nil {
+		return nil, err
+	}
+
+	if code == "" {
+		return nil, errors.New("Authorization code cannot be empty")
+	}
+
+	if codeVerifier == "" {
+		return nil, errors.New("PKCE code verifier cannot be empty")
+	}
+
+	if redirectURI == "" {
+		return nil, errors.New("Redirect URI cannot be empty")
+	}
+
+	values := url.Values{}
+	values.Add("grant_type", "authorization_code")
+	values.Add("code", code)
+	values.Add("code_verifier", codeVerifier)
+	values.Add("redirect_uri", redirectURI)
+
+	// An authorization code and its verifier can only be redeemed once, so a
+	// failed exchange is not retried.
+	token, _, err := u.doFetchToken(newRequest, values)
+	if err != nil {
+		logger.Error("error-exchanging-authorization-code", err)
+		return nil, err
+	}
+
+	logger.Debug("successfully-exchanged-authorization-code")
+	return token, nil
+}
+
+// codeChallenge derives the PKCE S256 challenge (RFC 7636) for a verifier.
+func codeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
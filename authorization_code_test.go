@@ -0,0 +1,193 @@
+package uaa_go_client_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/config"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Authorization code grant", func() {
+	var (
+		client uaa_go_client.Client
+	)
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			MaxNumberOfRetries:    DefaultMaxNumberOfRetries,
+			RetryInterval:         DefaultRetryInterval,
+			ExpirationBufferInSec: DefaultExpirationBufferTime,
+			RequestTimeout:        DefaultRequestTimeout,
+		}
+		server = ghttp.NewServer()
+
+		url, err := url.Parse(server.URL())
+		Expect(err).ToNot(HaveOccurred())
+		cfg.UaaEndpoint = "http://" + url.Host
+
+		cfg.ClientName = "client-name"
+		cfg.ClientSecret = "client-secret"
+		clock = fakeclock.NewFakeClock(time.Now())
+		logger = lagertest.NewTestLogger("test")
+
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("AuthorizationCodeURL", func() {
+		It("builds the authorize URL with state, nonce and a S256 PKCE challenge", func() {
+			request, err := client.AuthorizationCodeURL("https://example.com/callback", uaa_go_client.WithScopes("openid", "roles"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(request.State).NotTo(BeEmpty())
+			Expect(request.Nonce).NotTo(BeEmpty())
+			Expect(len(request.CodeVerifier)).To(BeNumerically(">=", 43))
+
+			authorizeURL, err := url.Parse(request.URL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(authorizeURL.Scheme + "://" + authorizeURL.Host).To(Equal(cfg.UaaEndpoint))
+			Expect(authorizeURL.Path).To(Equal("/oauth/authorize"))
+
+			sum := sha256.Sum256([]byte(request.CodeVerifier))
+			query := authorizeURL.Query()
+			Expect(query.Get("client_id")).To(Equal("client-name"))
+			Expect(query.Get("response_type")).To(Equal("code"))
+			Expect(query.Get("redirect_uri")).To(Equal("https://example.com/callback"))
+			Expect(query.Get("state")).To(Equal(request.State))
+			Expect(query.Get("nonce")).To(Equal(request.Nonce))
+			Expect(query.Get("code_challenge")).To(Equal(base64.RawURLEncoding.EncodeToString(sum[:])))
+			Expect(query.Get("code_challenge_method")).To(Equal("S256"))
+			Expect(query.Get("scope")).To(Equal("openid roles"))
+
+			Expect(server.ReceivedRequests()).To(HaveLen(0))
+		})
+
+		It("generates fresh values for every request", func() {
+			first, err := client.AuthorizationCodeURL("https://example.com/callback")
+			Expect(err).NotTo(HaveOccurred())
+			second, err := client.AuthorizationCodeURL("https://example.com/callback")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(first.State).NotTo(Equal(second.State))
+			Expect(first.Nonce).NotTo(Equal(second.Nonce))
+			Expect(first.CodeVerifier).NotTo(Equal(second.CodeVerifier))
+		})
+
+		It("returns an error when the redirect URI is empty", func() {
+			_, err := client.AuthorizationCodeURL("")
+			Expect(err).To(MatchError("Redirect URI cannot be empty"))
+		})
+	})
+
+	Describe("ExchangeAuthorizationCode", func() {
+		It("exchanges the code and verifier for a token", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/oauth/token"),
+					ghttp.VerifyBasicAuth("client-name", "client-secret"),
+					ghttp.VerifyContentType("application/x-www-form-urlencoded; charset=UTF-8"),
+					verifyBody("code=the-code&code_verifier=the-verifier&grant_type=authorization_code&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, &schema.Token{
+						AccessToken:  "the user token",
+						RefreshToken: "the refresh token",
+						IdToken:      "the id token",
+					}),
+				),
+			)
+
+			token, err := client.ExchangeAuthorizationCode("the-code", "the-verifier", "https://example.com/callback")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.AccessToken).To(Equal("the user token"))
+			Expect(token.RefreshToken).To(Equal("the refresh token"))
+			Expect(token.IdToken).To(Equal("the id token"))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		Context("when the client is public", func() {
+			BeforeEach(func() {
+				cfg.ClientName = "cf"
+				cfg.ClientSecret = ""
+
+				var err error
+				client, err = uaa_go_client.NewClient(logger, cfg, clock)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("sends the client_id without basic auth", func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/oauth/token"),
+						func(w http.ResponseWriter, req *http.Request) {
+							Expect(req.Header.Get("Authorization")).To(BeEmpty())
+						},
+						verifyBody("client_id=cf&code=the-code&code_verifier=the-verifier&grant_type=authorization_code&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback"),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, &schema.Token{
+							AccessToken: "the user token",
+						}),
+					),
+				)
+
+				token, err := client.ExchangeAuthorizationCode("the-code", "the-verifier", "https://example.com/callback")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(token.AccessToken).To(Equal("the user token"))
+				Expect(server.ReceivedRequests()).To(HaveLen(1))
+			})
+
+			It("still requires the client name", func() {
+				cfg.ClientName = ""
+
+				_, err := client.ExchangeAuthorizationCode("the-code", "the-verifier", "https://example.com/callback")
+				Expect(err).To(MatchError("OAuth Client ID cannot be empty"))
+				Expect(server.ReceivedRequests()).To(HaveLen(0))
+			})
+		})
+
+		It("returns an error when the code verifier is missing", func() {
+			_, err := client.ExchangeAuthorizationCode("the-code", "", "https://example.com/callback")
+			Expect(err).To(MatchError("PKCE code verifier cannot be empty"))
+			Expect(server.ReceivedRequests()).To(HaveLen(0))
+		})
+
+		It("returns an error when the redirect URI is missing", func() {
+			_, err := client.ExchangeAuthorizationCode("the-code", "the-verifier", "")
+			Expect(err).To(MatchError("Redirect URI cannot be empty"))
+			Expect(server.ReceivedRequests()).To(HaveLen(0))
+		})
+
+		It("does not retry the exchange when UAA fails", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusInternalServerError, "error"),
+			)
+
+			_, err := client.ExchangeAuthorizationCode("the-code", "the-verifier", "https://example.com/callback")
+			Expect(err).To(MatchError("status code: 500, body: error"))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("returns the UAA error when the code is rejected", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusBadRequest, `{"error":"invalid_grant"}`),
+			)
+
+			_, err := client.ExchangeAuthorizationCode("used-code", "the-verifier", "https://example.com/callback")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(`status code: 400, body: {"error":"invalid_grant"}`))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+})
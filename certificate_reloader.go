@@ -0,0 +1,72 @@
+package uaa_go_client
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// certificateReloader serves the client certificate presented to UAA and
+// reloads it from disk whenever the certificate or key file changes.
+type certificateReloader struct {
+	certFile string
+	keyFile  string
+
+	lock        sync.Mutex
+	cert        *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+}
+
+func newCertificateReloader(certFile, keyFile string) (*certificateReloader, error) {
+	if certFile == "" || keyFile == "" {
+		return nil, errors.New("Both client cert and client key must be provided")
+	}
+
+	reloader := &certificateReloader{certFile: certFile, keyFile: keyFile}
+	if err := reloader.reloadIfModified(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+func (r *certificateReloader) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err := r.reloadIfModified(); err != nil && r.cert == nil {
+		return nil, err
+	}
+	return r.cert, nil
+}
+
+// reloadIfModified must be called with the lock held, except from the constructor.
+// A failed reload keeps serving the previously loaded certificate.
+func (r *certificateReloader) reloadIfModified() error {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return fmt.Errorf("failed read client cert file: %s", err.Error())
+	}
+
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed read client key file: %s", err.Error())
+	}
+
+	if r.cert != nil && certInfo.ModTime().Equal(r.certModTime) && keyInfo.ModTime().Equal(r.keyModTime) {
+		return nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load client cert and key: %s", err.Error())
+	}
+
+	r.cert = &cert
+	r.certModTime = certInfo.ModTime()
+	r.keyModTime = keyInfo.ModTime()
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -26,33 +27,70 @@ import (
 
 var ErrClientAlreadyExists = errors.New("Client already exists")
 
-type uaaKey struct {
-	Alg   string `json:"alg"`
-	Value string `json:"value"`
-}
+// DefaultMinKeyRefetchInterval is used when config.MinKeyRefetchInterval is
+// not set.
+const DefaultMinKeyRefetchInterval = 30 * time.Second
+
+// ErrInvalidAudience is returned when a token was not issued for any of the
+// configured audiences.
+var ErrInvalidAudience = errors.New("Token audience is not allowed")
 
 //go:generate counterfeiter -o fakes/fake_client.go . Client
 type Client interface {
 	FetchToken(forceUpdate bool) (*schema.Token, error)
+	FetchTokenForScopes(scopes []string, forceUpdate bool) (*schema.Token, error)
+	FetchTokenWithAssertion(assertion string, scopes ...string) (*schema.Token, error)
+	ExchangeToken(request *schema.TokenExchangeRequest) (*schema.Token, error)
+	FetchUserToken(username, password string, opts ...TokenOption) (*schema.Token, error)
+	FetchPasscodeToken(passcode string, opts ...TokenOption) (*schema.Token, error)
+	FetchUserTokenForClient(userAccessToken, targetClientId string, scopes ...string) (*schema.Token, error)
+	RefreshToken(refreshToken string) (*schema.Token, error)
+	AuthorizationCodeURL(redirectURI string, opts ...TokenOption) (*schema.AuthorizationRequest, error)
+	ExchangeAuthorizationCode(code, codeVerifier, redirectURI string) (*schema.Token, error)
 	FetchKey() (string, error)
+	FetchKeySet() (*schema.JSONWebKeySet, error)
 	DecodeToken(uaaToken string, desiredPermissions ...string) error
+	VerifyToken(uaaToken string, opts ...VerifyOption) (*schema.Claims, error)
+	VerifyIDToken(idToken, nonce, accessToken string) (*schema.IDTokenClaims, error)
+	IntrospectToken(token string) (*schema.TokenIntrospection, error)
+	RevokeToken(tokenId string) error
+	RevokeUserTokens(userId string) error
+	RevokeClientTokens(clientId string) error
+	RevokeUserClientTokens(userId, clientId string) error
+	FetchUserInfo(accessToken string) (*schema.UserInfo, error)
 	RegisterOauthClient(*schema.OauthClient) (*schema.OauthClient, error)
 	FetchIssuer() (string, error)
+	FetchOpenIDConfiguration(forceUpdate bool) (*schema.OpenIDConfiguration, error)
+	ForZone(zone schema.IdentityZone) Client
+	Close()
 }
 
 type UaaClient struct {
-	clock            clock
-	config           *config.Config
-	client           *http.Client
-	cachedToken      *schema.Token
+	clock           clock
+	config          *config.Config
+	client          *http.Client
+	cachedTokens    map[string]*cachedToken
+	lock            *sync.Mutex
+	logger          lager.Logger
+	keys            keySet
+	rwlock          sync.RWMutex
+	issuer          string
+	assertionSigner *clientAssertionSigner
+	pinnedKeys      keySet
+	keyRefresher    *keyRefresher
+	keyRefetcher    *keyRefetcher
+	discovery       *cachedOpenIDConfiguration
+	discoveryRetry  time.Time
+	zone            schema.IdentityZone
+	zones           *zoneRegistry
+}
+
+type cachedToken struct {
+	token            *schema.Token
 	refetchTokenTime int64
-	lock             *sync.Mutex
-	logger           lager.Logger
-	uaaPublicKey     string
-	rwlock           sync.RWMutex
-	issuer           string
 }
 
+// Deprecated: use schema.OpenIDConfiguration instead.
 type OpenIDConfig struct {
 	Issuer string `json:"issuer"`
 }
@@ -80,12 +118,25 @@ func NewClient(logger lager.Logger, cfg *config.Config, clock clock) (Client, er
 		return nil, err
 	}
 
+	err = cfg.CheckSigningAlgorithms()
+	if err != nil {
+		return nil, err
+	}
+
+	// UAA only reveals symmetric keys to clients authenticating with a secret.
+	if cfg.AllowSymmetricSigningKeys && cfg.ClientAuthMethod != "" && cfg.ClientAuthMethod != config.ClientAuthSecretBasic {
+		return nil, errors.New("Symmetric signing keys require client_secret_basic client authentication")
+	}
+
 	if uri.Scheme == "https" {
 		client, err = newSecureClient(cfg)
 		if err != nil {
 			return nil, err
 		}
 	} else {
+		if cfg.ClientAuthMethod == config.ClientAuthTLS {
+			return nil, errors.New("Mutual TLS client authentication requires an https UAA endpoint")
+		}
 		client = &http.Client{}
 	}
 
@@ -105,13 +156,56 @@ func NewClient(logger lager.Logger, cfg *config.Config, clock clock) (Client, er
 		logger.Info("Expiration buffer in seconds set to default", lager.Data{"value": config.DefaultExpirationBufferInSec})
 	}
 
-	return &UaaClient{
-		logger: logger,
-		config: cfg,
-		client: client,
-		clock:  clock,
-		lock:   new(sync.Mutex),
-	}, nil
+	if cfg.ClockSkewLeeway == 0 {
+		cfg.ClockSkewLeeway = config.DefaultClockSkewLeeway
+		logger.Debug("Clock skew leeway set to default", lager.Data{"value": config.DefaultClockSkewLeeway.String()})
+	}
+
+	var assertionSigner *clientAssertionSigner
+	if cfg.ClientAuthMethod == config.ClientAuthPrivateKeyJWT && cfg.ClientAssertionKey != "" {
+		assertionSigner, err = newClientAssertionSigner(cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var pinnedKeys keySet
+	if cfg.VerificationKeysFile != "" {
+		if cfg.Issuer == "" {
+			return nil, errors.New("Pinned verification keys require a pinned issuer")
+		}
+		pinnedKeys, err = loadPinnedKeys(logger, cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	uaaClient := &UaaClient{
+		logger:          logger,
+		config:          cfg,
+		client:          client,
+		clock:           clock,
+		lock:            new(sync.Mutex),
+		cachedTokens:    map[string]*cachedToken{},
+		assertionSigner: assertionSigner,
+		pinnedKeys:      pinnedKeys,
+		keyRefetcher:    &keyRefetcher{},
+		issuer:          cfg.Issuer,
+		zone: schema.IdentityZone{
+			Id:        cfg.IdentityZoneId,
+			Subdomain: cfg.IdentityZoneSubdomain,
+		},
+	}
+	uaaClient.zones = &zoneRegistry{
+		clients: map[schema.IdentityZone]*UaaClient{uaaClient.zone: uaaClient},
+	}
+
+	if cfg.KeyRefreshInterval > 0 && pinnedKeys == nil {
+		uaaClient.keyRefresher = newKeyRefresher(cfg.KeyRefreshInterval)
+		go uaaClient.keyRefresher.run(uaaClient)
+	}
+
+	return uaaClient, nil
 }
 
 func newSecureClient(cfg *config.Config) (*http.Client, error) {
@@ -129,6 +223,14 @@ func newSecureClient(cfg *config.Config) (*http.Client, error) {
 		tlsConfig.RootCAs = caCertPool
 	}
 
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		reloader, err := newCertificateReloader(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.GetClientCertificate = reloader.GetClientCertificate
+	}
+
 	tr := &http.Transport{
 		TLSClientConfig: tlsConfig,
 	}
@@ -137,49 +239,29 @@ func newSecureClient(cfg *config.Config) (*http.Client, error) {
 	return client, nil
 }
 
+// FetchIssuer returns the configured issuer, or else the issuer from UAA's
+// discovery document, which is cached for DiscoveryCacheTTL.
 func (u *UaaClient) FetchIssuer() (string, error) {
-	logger := u.logger.Session("uaa-client")
-	fetchOpenIdURL := fmt.Sprintf("%s/.well-known/openid-configuration", u.config.UaaEndpoint)
-	logger.Info("started-fetching-openId-metadata", lager.Data{"endpoint": fetchOpenIdURL})
-
-	request, err := http.NewRequest("GET", fetchOpenIdURL, nil)
-	if err != nil {
-		return "", err
+	if u.config.Issuer != "" {
+		return u.config.Issuer, nil
 	}
-	trace.DumpRequest(request)
-	resp, err := u.client.Do(request)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	trace.DumpResponse(resp)
-	logger.Info("finished-fetching-openId-metatdata", lager.Data{"status-code": resp.StatusCode})
 
-	body, err := ioutil.ReadAll(resp.Body)
+	discovery, err := u.FetchOpenIDConfiguration(false)
 	if err != nil {
 		return "", err
 	}
-
-	if resp.StatusCode != http.StatusOK {
-		return "", errors.New(fmt.Sprintf("status code: %d, body: %s", resp.StatusCode, body))
-	}
-
-	data := &OpenIDConfig{}
-	err = json.Unmarshal(body, data)
-	if err != nil {
-		return "", err
-	}
-
-	logger.Info("successfully-received-issuer")
-	u.updateIssuer(data.Issuer)
-	return data.Issuer, nil
+	return discovery.Issuer, nil
 }
 
 func (u *UaaClient) FetchToken(forceUpdate bool) (*schema.Token, error) {
+	return u.FetchTokenForScopes(nil, forceUpdate)
+}
+
+func (u *UaaClient) FetchTokenForScopes(scopes []string, forceUpdate bool) (*schema.Token, error) {
 	logger := u.logger.Session("uaa-client")
-	tokenURL := fmt.Sprintf("%s/oauth/token", u.config.UaaEndpoint)
-	logger.Debug("started-fetching-token", lager.Data{"endpoint": tokenURL, "force-update": forceUpdate})
+	tokenURL := u.tokenURL(logger)
+	scopeKey := scopeSetKey(scopes)
+	logger.Debug("started-fetching-token", lager.Data{"endpoint": tokenURL, "force-update": forceUpdate, "scopes": scopeKey})
 
 	if err := u.config.CheckCredentials(); err != nil {
 		return nil, err
@@ -188,17 +270,40 @@ func (u *UaaClient) FetchToken(forceUpdate bool) (*schema.Token, error) {
 	u.lock.Lock()
 	defer u.lock.Unlock()
 
-	if !forceUpdate && u.canReturnCachedToken() {
+	if !forceUpdate && u.canReturnCachedToken(scopeKey) {
 		logger.Debug("using-cached-token")
-		return u.cachedToken, nil
+		return u.cachedTokens[scopeKey].token, nil
+	}
+
+	values := url.Values{}
+	values.Add("grant_type", "client_credentials")
+	if scopeKey != "" {
+		values.Add("scope", scopeKey)
+	}
+	token, err := u.fetchTokenWithRetries(logger, values)
+	if err != nil {
+		return nil, err
 	}
 
+	logger.Debug("successfully-fetched-token")
+	u.updateCachedToken(scopeKey, token)
+	return token, nil
+}
+
+// requestBuilder creates an authenticated form POST to a UAA endpoint.
+type requestBuilder func(endpoint string, values url.Values) (*http.Request, error)
+
+func (u *UaaClient) fetchTokenWithRetries(logger lager.Logger, values url.Values) (*schema.Token, error) {
+	return u.fetchTokenWithRetriesUsing(logger, u.newClientAuthenticatedRequest, values)
+}
+
+func (u *UaaClient) fetchTokenWithRetriesUsing(logger lager.Logger, newRequest requestBuilder, values url.Values) (*schema.Token, error) {
 	retry := true
 	var retryCount uint32 = 0
 	var token *schema.Token
 	var err error
 	for retry == true {
-		token, retry, err = u.doFetchToken()
+		token, retry, err = u.doFetchToken(newRequest, values)
 		if token != nil {
 			break
 		}
@@ -217,29 +322,20 @@ func (u *UaaClient) FetchToken(forceUpdate bool) (*schema.Token, error) {
 		}
 	}
 
-	logger.Debug("successfully-fetched-token")
-	u.updateCachedToken(token)
 	return token, nil
 }
 
-func (u *UaaClient) doFetchToken() (*schema.Token, bool, error) {
+func (u *UaaClient) doFetchToken(newRequest requestBuilder, values url.Values) (*schema.Token, bool, error) {
 	logger := u.logger.Session("uaa-client")
-	values := url.Values{}
-	values.Add("grant_type", "client_credentials")
-	requestBody := values.Encode()
-	tokenURL := fmt.Sprintf("%s/oauth/token", u.config.UaaEndpoint)
-	request, err := http.NewRequest("POST", tokenURL, bytes.NewBuffer([]byte(requestBody)))
+	tokenURL := u.tokenURL(logger)
+	request, err := newRequest(tokenURL, values)
 	if err != nil {
 		return nil, false, err
 	}
-
-	request.SetBasicAuth(u.config.ClientName, u.config.ClientSecret)
-	request.Header.Add("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8")
-	request.Header.Add("Accept", "application/json; charset=utf-8")
 	trace.DumpRequest(request)
 
 	logger.Info("fetch-token-from-uaa-start", lager.Data{"endpoint": request.URL})
-	resp, err := u.client.Do(request)
+	resp, err := u.do(request)
 	if err != nil {
 		return nil, true, err
 	}
@@ -271,18 +367,36 @@ func (u *UaaClient) doFetchToken() (*schema.Token, bool, error) {
 
 func (u *UaaClient) FetchKey() (string, error) {
 	logger := u.logger.Session("uaa-client")
-	getKeyUrl := fmt.Sprintf("%s/token_key", u.config.UaaEndpoint)
+	uaaKey, keys, err := u.fetchLegacyKey(logger)
+	if err != nil {
+		return "", err
+	}
+	u.setKeySet(keys)
+
+	logger.Info("fetch-key-successful")
+	return uaaKey.Value, nil
+}
+
+// fetchLegacyKey reads the single active key from /token_key.
+func (u *UaaClient) fetchLegacyKey(logger lager.Logger) (*schema.UaaKey, keySet, error) {
+	getKeyUrl := u.tokenKeyURL(logger)
 
 	logger.Info("fetch-key-starting", lager.Data{"endpoint": getKeyUrl})
 
-	resp, err := u.client.Get(getKeyUrl)
+	request, err := u.newKeyRequest(getKeyUrl)
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
 
+	resp, err := u.do(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
 	if resp.StatusCode != http.StatusOK {
 		err = fmt.Errorf("http error: status code: %d", resp.StatusCode)
-		return "", err
+		return nil, nil, err
 	}
 
 	decoder := json.NewDecoder(resp.Body)
@@ -290,112 +404,50 @@ func (u *UaaClient) FetchKey() (string, error) {
 	uaaKey := schema.UaaKey{}
 	err = decoder.Decode(&uaaKey)
 	if err != nil {
-		return "", errors.New("unmarshalling error: " + err.Error())
+		return nil, nil, errors.New("unmarshalling error: " + err.Error())
 	}
 
-	if err = checkPublicKey(uaaKey.Value); err != nil {
-		return "", err
+	key, err := parseVerificationKey(uaaKey, u.config.AllowSymmetricSigningKeys)
+	if err != nil {
+		return nil, nil, err
 	}
-
-	u.rwlock.Lock()
-	defer u.rwlock.Unlock()
-	u.uaaPublicKey = uaaKey.Value
-
-	logger.Info("fetch-key-successful")
-	return uaaKey.Value, nil
+	return &uaaKey, keySet{key}, nil
 }
 
 func (u *UaaClient) DecodeToken(uaaToken string, desiredPermissions ...string) error {
-	logger := u.logger.Session("uaa-client")
-	logger.Debug("decode-token-started")
-	defer logger.Debug("decode-token-completed")
-	var err error
-	jwtToken, err := checkTokenFormat(uaaToken)
-	if err != nil {
-		return err
-	}
-
-	var (
-		token            *jwt.Token
-		uaaKey           string
-		forceUaaKeyFetch bool
-	)
-
-	for i := 0; i < 2; i++ {
-		uaaKey, err = u.getUaaTokenKey(logger, forceUaaKeyFetch)
-
-		if err == nil {
-			token, err = jwt.Parse(jwtToken, func(t *jwt.Token) (interface{}, error) {
-				if !u.isValidSigningMethod(t) {
-					return nil, errors.New("invalid signing method")
-				}
-				if !u.isValidIssuer(t) {
-					return nil, errors.New("invalid issuer")
-				}
-
-				pubKey, err := jwt.ParseRSAPublicKeyFromPEM([]byte(uaaKey))
-				if err != nil {
-					return nil, err
-				}
-
-				return pubKey, nil
-			})
-
-			if err != nil {
-				logger.Error("decode-token-failed", err)
-				if matchesError(err, jwt.ValidationErrorSignatureInvalid) {
-					forceUaaKeyFetch = true
-					continue
-				}
-				if matchesError(err, jwt.ValidationErrorIssuedAt) {
-					logger.Info("decode-token-ignoring-issued-at-validation")
-					err = nil
-					break
-				}
-			}
-		}
-
-		break
-	}
+	_, err := u.VerifyToken(uaaToken, RequireAnyScope(desiredPermissions...))
+	return err
+}
 
-	if err != nil {
-		return err
+func (u *UaaClient) isValidAudience(token *jwt.Token) bool {
+	if len(u.config.Audiences) == 0 {
+		return true
 	}
 
-	hasPermission := false
-	var permissions interface{}
-	if claims, ok := token.Claims.(jwt.MapClaims); ok {
-		permissions = claims["scope"]
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return false
 	}
-	a := permissions.([]interface{})
-
-	for _, permission := range a {
-		for _, desiredPermission := range desiredPermissions {
-			if permission.(string) == desiredPermission {
-				hasPermission = true
-				break
-			}
+	for _, audience := range u.config.Audiences {
+		if claims.VerifyAudience(audience, true) {
+			return true
 		}
 	}
-
-	if !hasPermission {
-		err = errors.New("Token does not have '" + strings.Join(desiredPermissions, "', '") + "' scope")
-		return err
-	}
-
-	return nil
+	return false
 }
 
 func (u *UaaClient) isValidIssuer(token *jwt.Token) bool {
-	if u.issuer == "" {
+	issuer := u.getIssuer()
+	if issuer == "" {
 		_, err := u.FetchIssuer()
 		if err != nil {
 			return false
 		}
+		issuer = u.getIssuer()
 	}
 
 	if claims, ok := token.Claims.(jwt.MapClaims); ok {
-		return claims.VerifyIssuer(u.issuer, true)
+		return claims.VerifyIssuer(issuer, true)
 	}
 	return false
 }
@@ -405,12 +457,26 @@ func (u *UaaClient) isValidSigningMethod(token *jwt.Token) bool {
 		return true
 	}
 
-	switch token.Method {
-	case jwt.SigningMethodRS256, jwt.SigningMethodRS384, jwt.SigningMethodRS512:
-		return true
-	default:
-		return false
+	alg := token.Method.Alg()
+	if isSymmetricAlg(alg) {
+		if !u.config.AllowSymmetricSigningKeys {
+			return false
+		}
+		if len(u.config.AllowedSigningAlgorithms) == 0 {
+			return true
+		}
 	}
+
+	allowed := u.config.AllowedSigningAlgorithms
+	if len(allowed) == 0 {
+		allowed = config.DefaultSigningAlgorithms
+	}
+	for _, allowedAlg := range allowed {
+		if alg == allowedAlg {
+			return true
+		}
+	}
+	return false
 }
 
 func (u *UaaClient) RegisterOauthClient(oauthClient *schema.OauthClient) (*schema.OauthClient, error) {
@@ -426,7 +492,7 @@ func (u *UaaClient) RegisterOauthClient(oauthClient *schema.OauthClient) (*schem
 	request.Header.Add("Accept", "application/json; charset=utf-8")
 	request.Header.Add("Authorization", "bearer "+token.AccessToken)
 
-	response, err := u.client.Do(request)
+	response, err := u.do(request)
 
 	if err != nil {
 		return nil, err
@@ -457,14 +523,32 @@ func (u *UaaClient) RegisterOauthClient(oauthClient *schema.OauthClient) (*schem
 	return returnedOauthClient, nil
 }
 
-func (u *UaaClient) canReturnCachedToken() bool {
-	return u.cachedToken != nil && u.clock.Now().Unix() < u.refetchTokenTime
+func (u *UaaClient) canReturnCachedToken(scopeKey string) bool {
+	cached, ok := u.cachedTokens[scopeKey]
+	return ok && u.clock.Now().Unix() < cached.refetchTokenTime
 }
 
-func (u *UaaClient) updateCachedToken(token *schema.Token) {
-	u.logger.Debug("caching-token")
-	u.cachedToken = token
-	u.refetchTokenTime = u.clock.Now().Unix() + (token.ExpiresIn - u.config.ExpirationBufferInSec)
+func (u *UaaClient) updateCachedToken(scopeKey string, token *schema.Token) {
+	u.logger.Debug("caching-token", lager.Data{"scopes": scopeKey})
+	u.cachedTokens[scopeKey] = &cachedToken{
+		token:            token,
+		refetchTokenTime: u.clock.Now().Unix() + (token.ExpiresIn - u.config.ExpirationBufferInSec),
+	}
+}
+
+// scopeSetKey normalizes a scope set so that the same scopes in any order
+// share a cache entry. The empty key stands for all of the client's authorities.
+func scopeSetKey(scopes []string) string {
+	unique := map[string]bool{}
+	sorted := []string{}
+	for _, scope := range scopes {
+		if scope != "" && !unique[scope] {
+			unique[scope] = true
+			sorted = append(sorted, scope)
+		}
+	}
+	sort.Strings(sorted)
+	return strings.Join(sorted, " ")
 }
 
 func checkPublicKey(key string) error {
@@ -475,7 +559,15 @@ func checkPublicKey(key string) error {
 	return nil
 }
 
+func (u *UaaClient) getIssuer() string {
+	u.rwlock.RLock()
+	defer u.rwlock.RUnlock()
+	return u.issuer
+}
+
 func (u *UaaClient) updateIssuer(issuer string) {
+	u.rwlock.Lock()
+	defer u.rwlock.Unlock()
 	u.issuer = issuer
 }
 
@@ -499,28 +591,3 @@ func matchesError(err error, errorType uint32) bool {
 	}
 	return false
 }
-
-func (u *UaaClient) getUaaTokenKey(logger lager.Logger, forceFetch bool) (string, error) {
-	if u.getUaaPublicKey() == "" || forceFetch {
-		logger.Debug("fetching-new-uaa-key")
-		key, err := u.FetchKey()
-		if err != nil {
-			return key, err
-		}
-
-		if u.getUaaPublicKey() == key {
-			logger.Debug("Fetched the same verification key from UAA")
-		} else {
-			logger.Debug("Fetched a different verification key from UAA")
-		}
-		return key, nil
-	}
-
-	return u.getUaaPublicKey(), nil
-}
-
-func (u *UaaClient) getUaaPublicKey() string {
-	u.rwlock.RLock()
-	defer u.rwlock.RUnlock()
-	return u.uaaPublicKey
-}
@@ -0,0 +1,171 @@
+package uaa_go_client
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"code.cloudfoundry.org/uaa-go-client/config"
+)
+
+const (
+	clientAssertionType     = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	clientAssertionLifetime = 5 * time.Minute
+)
+
+type clientAssertionSigner struct {
+	method jwt.SigningMethod
+	key    interface{}
+	keyId  string
+}
+
+func newClientAssertionSigner(cfg *config.Config) (*clientAssertionSigner, error) {
+	keyBytes, err := ioutil.ReadFile(cfg.ClientAssertionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed read client assertion key file: %s", err.Error())
+	}
+
+	signer := &clientAssertionSigner{keyId: cfg.ClientAssertionKeyId}
+	if rsaKey, err := jwt.ParseRSAPrivateKeyFromPEM(keyBytes); err == nil {
+		signer.method = jwt.SigningMethodRS256
+		signer.key = rsaKey
+		return signer, nil
+	}
+
+	ecKey, err := jwt.ParseECPrivateKeyFromPEM(keyBytes)
+	if err != nil {
+		return nil, errors.New("Client assertion key must be a PEM encoded RSA or EC private key")
+	}
+
+	switch ecKey.Curve {
+	case elliptic.P256():
+		signer.method = jwt.SigningMethodES256
+	case elliptic.P384():
+		signer.method = jwt.SigningMethodES384
+	case elliptic.P521():
+		signer.method = jwt.SigningMethodES512
+	default:
+		return nil, errors.New("Unsupported client assertion key curve")
+	}
+	signer.key = ecKey
+	return signer, nil
+}
+
+// sign creates a short-lived client assertion (RFC 7523) for the given audience.
+func (s *clientAssertionSigner) sign(clientId, audience string, now time.Time) (string, error) {
+	jti, err := randomString()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(s.method, jwt.RegisteredClaims{
+		Issuer:    clientId,
+		Subject:   clientId,
+		Audience:  jwt.ClaimStrings{audience},
+		ID:        jti,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(clientAssertionLifetime)),
+	})
+	if s.keyId != "" {
+		token.Header["kid"] = s.keyId
+	}
+
+	return token.SignedString(s.key)
+}
+
+// newClientAuthenticatedRequest builds a form POST to a UAA endpoint that is
+// authenticated as the configured OAuth client.
+func (u *UaaClient) newClientAuthenticatedRequest(endpoint string, values url.Values) (*http.Request, error) {
+	form := url.Values{}
+	for key, value := range values {
+		form[key] = value
+	}
+
+	basicAuth := true
+	switch u.config.ClientAuthMethod {
+	case config.ClientAuthPrivateKeyJWT:
+		if u.assertionSigner == nil {
+			return nil, errors.New("Client assertion key is not loaded")
+		}
+
+		// UAA checks the assertion audience against its token endpoint, whichever
+		// endpoint is being called.
+		audience := u.tokenURL(u.logger.Session("uaa-client"))
+		assertion, err := u.assertionSigner.sign(u.config.ClientName, audience, u.clock.Now())
+		if err != nil {
+			return nil, err
+		}
+
+		form.Set("client_id", u.config.ClientName)
+		form.Set("client_assertion_type", clientAssertionType)
+		form.Set("client_assertion", assertion)
+		basicAuth = false
+	case config.ClientAuthTLS:
+		form.Set("client_id", u.config.ClientName)
+		basicAuth = false
+	}
+
+	request, err := http.NewRequest("POST", endpoint, bytes.NewBuffer([]byte(form.Encode())))
+	if err != nil {
+		return nil, err
+	}
+
+	if basicAuth {
+		request.SetBasicAuth(u.config.ClientName, u.config.ClientSecret)
+	}
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8")
+	request.Header.Add("Accept", "application/json; charset=utf-8")
+	return request, nil
+}
+
+// newBearerAuthenticatedRequest returns a requestBuilder that authenticates
+// with an access token instead of the configured OAuth client.
+func newBearerAuthenticatedRequest(accessToken string) requestBuilder {
+	return func(endpoint string, values url.Values) (*http.Request, error) {
+		request, err := http.NewRequest("POST", endpoint, bytes.NewBuffer([]byte(values.Encode())))
+		if err != nil {
+			return nil, err
+		}
+
+		request.Header.Add("Authorization", "bearer "+accessToken)
+		request.Header.Add("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8")
+		request.Header.Add("Accept", "application/json; charset=utf-8")
+		return request, nil
+	}
+}
+
+// isPublicClient reports whether the client is configured for client secret
+// authentication without a secret.
+func (u *UaaClient) isPublicClient() bool {
+	switch u.config.ClientAuthMethod {
+	case "", config.ClientAuthSecretBasic:
+		return u.config.ClientSecret == ""
+	}
+	return false
+}
+
+// newPublicClientRequest builds a form POST to a UAA endpoint for a public
+// client, which only sends its client_id.
+func (u *UaaClient) newPublicClientRequest(endpoint string, values url.Values) (*http.Request, error) {
+	form := url.Values{}
+	for key, value := range values {
+		form[key] = value
+	}
+	form.Set("client_id", u.config.ClientName)
+
+	request, err := http.NewRequest("POST", endpoint, bytes.NewBuffer([]byte(form.Encode())))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Add("Content-Type", "application/x-www-form-urlencoded; charset=UTF-8")
+	request.Header.Add("Accept", "application/json; charset=utf-8")
+	return request, nil
+}
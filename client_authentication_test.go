@@ -0,0 +1,248 @@
+package uaa_go_client_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/config"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+	"github.com/golang-jwt/jwt/v4"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Client authentication", func() {
+	var (
+		client  uaa_go_client.Client
+		keyFile string
+	)
+
+	writeKeyFile := func(keyPEM []byte) string {
+		f, err := ioutil.TempFile("", "uaa-go-client-assertion-key")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = f.Write(keyPEM)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Close()).To(Succeed())
+		return f.Name()
+	}
+
+	verifyClientAssertion := func(publicKey interface{}, expectedAlg string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			_, _, hasBasicAuth := r.BasicAuth()
+			Expect(hasBasicAuth).To(BeFalse())
+
+			Expect(r.ParseForm()).To(Succeed())
+			Expect(r.PostForm.Get("client_id")).To(Equal("client-name"))
+			Expect(r.PostForm.Get("client_assertion_type")).To(Equal("urn:ietf:params:oauth:client-assertion-type:jwt-bearer"))
+
+			claims := &jwt.RegisteredClaims{}
+			token, err := jwt.ParseWithClaims(r.PostForm.Get("client_assertion"), claims, func(t *jwt.Token) (interface{}, error) {
+				return publicKey, nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.Method.Alg()).To(Equal(expectedAlg))
+			Expect(token.Header["kid"]).To(Equal("assertion-key-1"))
+			Expect(claims.Issuer).To(Equal("client-name"))
+			Expect(claims.Subject).To(Equal("client-name"))
+			Expect(claims.Audience).To(ConsistOf(cfg.UaaEndpoint + "/oauth/token"))
+			Expect(claims.ID).NotTo(BeEmpty())
+			Expect(claims.ExpiresAt.Sub(claims.IssuedAt.Time)).To(Equal(5 * time.Minute))
+		}
+	}
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			MaxNumberOfRetries:    DefaultMaxNumberOfRetries,
+			RetryInterval:         DefaultRetryInterval,
+			ExpirationBufferInSec: DefaultExpirationBufferTime,
+			RequestTimeout:        DefaultRequestTimeout,
+		}
+		server = ghttp.NewServer()
+
+		url, err := url.Parse(server.URL())
+		Expect(err).ToNot(HaveOccurred())
+		cfg.UaaEndpoint = "http://" + url.Host
+
+		cfg.ClientName = "client-name"
+		cfg.ClientAuthMethod = config.ClientAuthPrivateKeyJWT
+		cfg.ClientAssertionKeyId = "assertion-key-1"
+		clock = fakeclock.NewFakeClock(time.Now())
+		logger = lagertest.NewTestLogger("test")
+	})
+
+	AfterEach(func() {
+		server.Close()
+		if keyFile != "" {
+			Expect(os.Remove(keyFile)).To(Succeed())
+			keyFile = ""
+		}
+	})
+
+	Context("when the assertion key is an RSA key", func() {
+		var privateKey *rsa.PrivateKey
+
+		BeforeEach(func() {
+			var err error
+			privateKey, _, err = generateRSAKeyPair()
+			Expect(err).NotTo(HaveOccurred())
+			keyFile = writeKeyFile(privateKeyToPEM(privateKey))
+			cfg.ClientAssertionKey = keyFile
+
+			client, err = uaa_go_client.NewClient(logger, cfg, clock)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("authenticates client credentials grants with a signed client assertion", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/oauth/token"),
+					verifyClientAssertion(&privateKey.PublicKey, "RS256"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, &schema.Token{AccessToken: "the token", ExpiresIn: 3600}),
+				),
+			)
+
+			token, err := client.FetchToken(true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.AccessToken).To(Equal("the token"))
+		})
+
+		It("authenticates other grants with a signed client assertion", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					verifyClientAssertion(&privateKey.PublicKey, "RS256"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, &schema.Token{AccessToken: "the user token"}),
+				),
+				ghttp.CombineHandlers(
+					verifyClientAssertion(&privateKey.PublicKey, "RS256"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, &schema.Token{AccessToken: "the refreshed token"}),
+				),
+			)
+
+			_, err := client.FetchUserToken("user", "password")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.RefreshToken("refresh-token")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("authenticates introspection with an assertion for the token endpoint", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/introspect"),
+					verifyClientAssertion(&privateKey.PublicKey, "RS256"),
+					ghttp.RespondWith(http.StatusNotFound, ""),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/check_token"),
+					verifyClientAssertion(&privateKey.PublicKey, "RS256"),
+					ghttp.RespondWith(http.StatusOK, `{"sub":"user-guid"}`),
+				),
+			)
+
+			introspection, err := client.IntrospectToken("the-token")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(introspection.Active).To(BeTrue())
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("uses a fresh assertion for every request", func() {
+			var assertions []string
+			recordAssertion := func(w http.ResponseWriter, r *http.Request) {
+				Expect(r.ParseForm()).To(Succeed())
+				assertions = append(assertions, r.PostForm.Get("client_assertion"))
+			}
+			server.AppendHandlers(
+				ghttp.CombineHandlers(recordAssertion, ghttp.RespondWithJSONEncoded(http.StatusOK, &schema.Token{AccessToken: "1"})),
+				ghttp.CombineHandlers(recordAssertion, ghttp.RespondWithJSONEncoded(http.StatusOK, &schema.Token{AccessToken: "2"})),
+			)
+
+			_, err := client.FetchToken(true)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.FetchToken(true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(assertions).To(HaveLen(2))
+			Expect(assertions[0]).NotTo(Equal(assertions[1]))
+		})
+	})
+
+	Context("when the assertion key is an EC key", func() {
+		It("signs the assertion with the matching ES algorithm", func() {
+			privateKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+			keyBytes, err := x509.MarshalECPrivateKey(privateKey)
+			Expect(err).NotTo(HaveOccurred())
+			keyFile = writeKeyFile(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}))
+			cfg.ClientAssertionKey = keyFile
+
+			client, err = uaa_go_client.NewClient(logger, cfg, clock)
+			Expect(err).NotTo(HaveOccurred())
+
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					verifyClientAssertion(&privateKey.PublicKey, "ES384"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, &schema.Token{AccessToken: "the token"}),
+				),
+			)
+
+			_, err = client.FetchToken(true)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when the assertion key is invalid", func() {
+		It("fails to create the client", func() {
+			keyFile = writeKeyFile([]byte("not a key"))
+			cfg.ClientAssertionKey = keyFile
+
+			_, err := uaa_go_client.NewClient(logger, cfg, clock)
+			Expect(err).To(MatchError("Client assertion key must be a PEM encoded RSA or EC private key"))
+		})
+
+		It("fails to create the client when the file cannot be read", func() {
+			cfg.ClientAssertionKey = "/non/existent/key.pem"
+
+			_, err := uaa_go_client.NewClient(logger, cfg, clock)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed read client assertion key file"))
+		})
+	})
+
+	Context("when the assertion key is not configured", func() {
+		It("returns an error without contacting UAA", func() {
+			var err error
+			client, err = uaa_go_client.NewClient(logger, cfg, clock)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.FetchToken(true)
+			Expect(err).To(MatchError("OAuth Client assertion key cannot be empty"))
+			Expect(server.ReceivedRequests()).To(HaveLen(0))
+		})
+	})
+
+	Context("when the authentication method is unknown", func() {
+		It("returns an error without contacting UAA", func() {
+			cfg.ClientAuthMethod = "client_secret_post"
+			var err error
+			client, err = uaa_go_client.NewClient(logger, cfg, clock)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.FetchToken(true)
+			Expect(err).To(MatchError("Unsupported client authentication method: client_secret_post"))
+			Expect(server.ReceivedRequests()).To(HaveLen(0))
+		})
+	})
+})
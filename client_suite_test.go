@@ -1,6 +1,7 @@
 package uaa_go_client_test
 
 import (
+	"crypto/rsa"
 	"fmt"
 
 	. "github.com/onsi/ginkgo"
@@ -10,6 +11,7 @@ import (
 
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"sync"
 	"time"
 
@@ -21,6 +23,8 @@ import (
 	"encoding/json"
 
 	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/onsi/gomega/gbytes"
 	"github.com/onsi/gomega/ghttp"
 )
@@ -32,6 +36,7 @@ func TestClient(t *testing.T) {
 
 const (
 	TokenKeyEndpoint            = "/token_key"
+	TokenKeysEndpoint           = "/token_keys"
 	OpenIDConfigEndpoint        = "/.well-known/openid-configuration"
 	DefaultMaxNumberOfRetries   = 3
 	DefaultRetryInterval        = 15 * time.Second
@@ -90,6 +95,73 @@ var getSuccessKeyFetchHandler = func(key string) http.HandlerFunc {
 	)
 }
 
+var getSuccessKeySetFetchHandler = func(key string) http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest("GET", TokenKeysEndpoint),
+		ghttp.RespondWith(http.StatusOK, fmt.Sprintf("{\"keys\": [{\"alg\":\"alg\", \"value\": \"%s\" }]}", key)),
+	)
+}
+
+// startUaaServer starts the test UAA server and points a fresh client config,
+// clock and logger at it.
+var startUaaServer = func() {
+	cfg = &config.Config{
+		MaxNumberOfRetries:    DefaultMaxNumberOfRetries,
+		RetryInterval:         DefaultRetryInterval,
+		ExpirationBufferInSec: DefaultExpirationBufferTime,
+		RequestTimeout:        DefaultRequestTimeout,
+	}
+	server = ghttp.NewServer()
+
+	url, err := url.Parse(server.URL())
+	Expect(err).ToNot(HaveOccurred())
+	cfg.UaaEndpoint = "http://" + url.Host
+
+	cfg.ClientName = "client-name"
+	cfg.ClientSecret = "client-secret"
+	clock = fakeclock.NewFakeClock(time.Now())
+	logger = lagertest.NewTestLogger("test")
+}
+
+var getJSONWebKeySetFetchHandler = func(keys ...schema.UaaKey) http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest("GET", TokenKeysEndpoint),
+		ghttp.RespondWithJSONEncoded(http.StatusOK, schema.JSONWebKeySet{Keys: keys}),
+	)
+}
+
+var getIssuerFetchHandler = func() http.HandlerFunc {
+	return ghttp.CombineHandlers(
+		ghttp.VerifyRequest("GET", OpenIDConfigEndpoint),
+		ghttp.RespondWith(http.StatusOK, `{"issuer":"https://uaa.domain.com"}`),
+	)
+}
+
+var rsaVerificationKey = func(kid string, publicKey *rsa.PublicKey) schema.UaaKey {
+	publicKeyPEM, err := publicKeyToPEM(publicKey)
+	Expect(err).NotTo(HaveOccurred())
+	return schema.UaaKey{Kid: kid, Alg: "RS256", Value: string(publicKeyPEM)}
+}
+
+// tokenClaims returns the claims of a token the test clients accept.
+var tokenClaims = func() jwt.MapClaims {
+	return jwt.MapClaims{
+		"scope": []string{"some.scope"},
+		"iss":   "https://uaa.domain.com",
+		"exp":   2491253686,
+	}
+}
+
+// signClaims signs claims with the given key id in the token header.
+var signClaims = func(method jwt.SigningMethod, key interface{}, kid string, claims jwt.Claims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	Expect(err).NotTo(HaveOccurred())
+	return signed
+}
+
 // This function is flaky, there's a race condition between the FetchToken and
 // the expectation on the recieved requests size. The tests calling it have been
 // marked as skiped since we are expecting to deprecate this repo around fall 2019.
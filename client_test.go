@@ -24,6 +24,7 @@ import (
 	"code.cloudfoundry.org/lager/lagertest"
 	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
 	"code.cloudfoundry.org/uaa-go-client/config"
+	"code.cloudfoundry.org/uaa-go-client/schema"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -185,10 +186,9 @@ var _ = Describe("UAA Client", func() {
 			Expect(err).ToNot(HaveOccurred())
 			cfg.UaaEndpoint = "http://" + url.Host
 
-			var uaaResponseStruct = struct {
-				Alg   string `json:"alg"`
-				Value string `json:"value"`
-			}{"alg", string(publicKeyPEM)}
+			uaaResponseStruct := schema.JSONWebKeySet{Keys: []schema.UaaKey{
+				{Kid: "some-key-id", Alg: "RS256", Value: string(publicKeyPEM)},
+			}}
 			server.AppendHandlers(
 				ghttp.RespondWithJSONEncoded(
 					http.StatusOK,
@@ -284,17 +284,16 @@ var _ = Describe("UAA Client", func() {
 				Expect(err).NotTo(HaveOccurred())
 				publicKeyPEM, err = publicKeyToPEM(publicKey)
 				Expect(err).NotTo(HaveOccurred())
-				var uaaResponseStruct = struct {
-					Alg   string `json:"alg"`
-					Value string `json:"value"`
-				}{"alg", string(publicKeyPEM)}
+				uaaResponseStruct := schema.JSONWebKeySet{Keys: []schema.UaaKey{
+					{Kid: "some-key-id", Alg: "RS256", Value: string(publicKeyPEM)},
+				}}
 				server.AppendHandlers(
 					ghttp.CombineHandlers(
 						ghttp.VerifyRequest("GET", OpenIDConfigEndpoint),
 						ghttp.RespondWith(http.StatusOK, fmt.Sprintf("{\"issuer\":\"https://uaa.domain.com\"}")),
 					),
 					ghttp.CombineHandlers(
-						ghttp.VerifyRequest("GET", "/token_key"),
+						ghttp.VerifyRequest("GET", TokenKeysEndpoint),
 						ghttp.RespondWithJSONEncoded(
 							http.StatusOK,
 							uaaResponseStruct,
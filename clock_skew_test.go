@@ -0,0 +1,131 @@
+package uaa_go_client_test
+
+import (
+	"crypto/rsa"
+	"time"
+
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/config"
+	"github.com/golang-jwt/jwt/v4"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Clock skew leeway", func() {
+	var (
+		client     uaa_go_client.Client
+		privateKey *rsa.PrivateKey
+		now        time.Time
+	)
+
+	bearerToken := func(claims jwt.MapClaims) string {
+		claims["iss"] = "https://uaa.domain.com"
+		claims["scope"] = []string{"some.scope"}
+		return "bearer " + signClaims(jwt.SigningMethodRS256, privateKey, "some-key-id", claims)
+	}
+
+	verifyValidationError := func(err error, errorType uint32) {
+		validationError, ok := err.(*jwt.ValidationError)
+		Expect(ok).To(BeTrue())
+		Expect(validationError.Errors & errorType).To(Equal(errorType))
+	}
+
+	BeforeEach(func() {
+		startUaaServer()
+		now = clock.Now()
+
+		var (
+			publicKey *rsa.PublicKey
+			err       error
+		)
+		privateKey, publicKey, err = generateRSAKeyPair()
+		Expect(err).NotTo(HaveOccurred())
+
+		server.AppendHandlers(
+			getJSONWebKeySetFetchHandler(rsaVerificationKey("some-key-id", publicKey)),
+			getIssuerFetchHandler(),
+		)
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("with the default leeway", func() {
+		It("accepts time claims a few seconds off", func() {
+			token := bearerToken(jwt.MapClaims{
+				"exp": now.Add(-5 * time.Second).Unix(),
+				"nbf": now.Add(5 * time.Second).Unix(),
+				"iat": now.Add(5 * time.Second).Unix(),
+			})
+			Expect(client.DecodeToken(token, "some.scope")).To(Succeed())
+		})
+
+		It("rejects time claims beyond the default leeway", func() {
+			token := bearerToken(jwt.MapClaims{
+				"iat": now.Add(config.DefaultClockSkewLeeway + time.Second).Unix(),
+			})
+			err := client.DecodeToken(token, "some.scope")
+			verifyValidationError(err, jwt.ValidationErrorIssuedAt)
+		})
+	})
+
+	Context("when the leeway is disabled", func() {
+		BeforeEach(func() {
+			cfg.ClockSkewLeeway = -1
+		})
+
+		It("rejects a token that expired a few seconds ago", func() {
+			token := bearerToken(jwt.MapClaims{"exp": now.Add(-5 * time.Second).Unix()})
+			err := client.DecodeToken(token, "some.scope")
+			verifyValidationError(err, jwt.ValidationErrorExpired)
+		})
+
+		It("rejects a token that is not valid yet", func() {
+			token := bearerToken(jwt.MapClaims{"nbf": now.Add(5 * time.Second).Unix()})
+			err := client.DecodeToken(token, "some.scope")
+			verifyValidationError(err, jwt.ValidationErrorNotValidYet)
+		})
+
+		It("validates against the client clock", func() {
+			token := bearerToken(jwt.MapClaims{"exp": now.Add(time.Minute).Unix()})
+			Expect(client.DecodeToken(token, "some.scope")).To(Succeed())
+
+			clock.Increment(2 * time.Minute)
+			err := client.DecodeToken(token, "some.scope")
+			verifyValidationError(err, jwt.ValidationErrorExpired)
+		})
+	})
+
+	Context("with a leeway", func() {
+		BeforeEach(func() {
+			cfg.ClockSkewLeeway = 2 * time.Minute
+		})
+
+		It("accepts time claims within the leeway", func() {
+			token := bearerToken(jwt.MapClaims{
+				"exp": now.Add(-90 * time.Second).Unix(),
+				"nbf": now.Add(90 * time.Second).Unix(),
+				"iat": now.Add(90 * time.Second).Unix(),
+			})
+			Expect(client.DecodeToken(token, "some.scope")).To(Succeed())
+		})
+
+		It("rejects time claims beyond the leeway", func() {
+			token := bearerToken(jwt.MapClaims{
+				"exp": now.Add(-3 * time.Minute).Unix(),
+				"iat": now.Add(3 * time.Minute).Unix(),
+			})
+			err := client.DecodeToken(token, "some.scope")
+			verifyValidationError(err, jwt.ValidationErrorExpired|jwt.ValidationErrorIssuedAt)
+		})
+	})
+})
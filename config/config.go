@@ -2,6 +2,7 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"net/url"
 	"time"
 )
@@ -9,19 +10,89 @@ import (
 const (
 	DefaultExpirationBufferInSec = 30
 	DefaultRequestTimeout        = 0 * time.Second
+	DefaultClockSkewLeeway       = 30 * time.Second
 )
 
+// Client authentication methods supported toward the UAA token endpoint.
+const (
+	ClientAuthSecretBasic   = "client_secret_basic"
+	ClientAuthPrivateKeyJWT = "private_key_jwt"
+	ClientAuthTLS           = "tls_client_auth"
+)
+
+// DefaultSigningAlgorithms are accepted when AllowedSigningAlgorithms is empty.
+var DefaultSigningAlgorithms = []string{"RS256", "RS384", "RS512"}
+
+var supportedSigningAlgorithms = map[string]bool{
+	"RS256": true, "RS384": true, "RS512": true,
+	"PS256": true, "PS384": true, "PS512": true,
+	"ES256": true, "ES384": true, "ES512": true,
+	"HS256": true, "HS384": true, "HS512": true,
+}
+
 type Config struct {
 	UaaEndpoint                   string `yaml:"uaa_endpoint"`
 	ClientName                    string `yaml:"client_name"`
 	ClientSecret                  string `yaml:"client_secret"`
+	ClientAuthMethod              string `yaml:"client_auth_method"`
+	ClientAssertionKey            string `yaml:"client_assertion_key"`
+	ClientAssertionKeyId          string `yaml:"client_assertion_key_id"`
 	CACerts                       string `yaml:"ca_certs"`
+	ClientCert                    string `yaml:"client_cert"`
+	ClientKey                     string `yaml:"client_key"`
+	IdentityZoneId                string `yaml:"identity_zone_id"`
+	IdentityZoneSubdomain         string `yaml:"identity_zone_subdomain"`
 	MaxNumberOfRetries            uint32
 	RetryInterval                 time.Duration
 	ExpirationBufferInSec         int64
 	SkipVerification              bool
-	InsecureAllowAnySigningMethod bool
+	InsecureAllowAnySigningMethod bool // Deprecated: use AllowedSigningAlgorithms instead.
 	RequestTimeout                time.Duration
+
+	// AllowSymmetricSigningKeys fetches verification keys with the client
+	// credentials and accepts HS256/HS384/HS512 keys shared by UAA. It
+	// requires client_secret_basic client authentication.
+	AllowSymmetricSigningKeys bool `yaml:"allow_symmetric_signing_keys"`
+
+	// AllowedSigningAlgorithms restricts the JWS algorithms accepted when
+	// decoding tokens. It defaults to DefaultSigningAlgorithms.
+	AllowedSigningAlgorithms []string `yaml:"allowed_signing_algorithms"`
+
+	// Audiences are the resource IDs this client accepts tokens for. When
+	// set, a token's aud claim must contain at least one of them.
+	Audiences []string `yaml:"audiences"`
+
+	// ClockSkewLeeway is the tolerated clock difference to UAA when checking
+	// the exp, nbf and iat claims of a token. Zero uses
+	// DefaultClockSkewLeeway and a negative value disables the leeway.
+	ClockSkewLeeway time.Duration `yaml:"clock_skew_leeway"`
+
+	// VerificationKeysFile pins the token verification keys to a JWKS or PEM
+	// file, and Issuer pins the expected token issuer. Tokens are then
+	// verified without contacting UAA. VerificationKeysFile requires Issuer.
+	VerificationKeysFile string `yaml:"verification_keys_file"`
+	Issuer               string `yaml:"issuer"`
+
+	// KeyRefreshInterval enables a background refresh of the verification
+	// keys. Call Close on the client returned by NewClient to stop it.
+	KeyRefreshInterval time.Duration `yaml:"key_refresh_interval"`
+
+	// MinKeyRefetchInterval limits how often a token that fails verification,
+	// or arrives before any keys could be fetched, can force a key fetch. Zero
+	// uses the client default and a negative value disables the limit.
+	MinKeyRefetchInterval time.Duration `yaml:"min_key_refetch_interval"`
+
+	// UseDiscoveredEndpoints sends requests to the endpoints advertised in
+	// UAA's OpenID discovery document instead of fixed paths under
+	// UaaEndpoint, for UAA deployments behind path-rewriting proxies. It is
+	// off by default: UAA often advertises its external URLs, which clients
+	// on the internal network (such as uaa.service.cf.internal) may not be
+	// able to reach, so existing deployments keep using UaaEndpoint.
+	UseDiscoveredEndpoints bool `yaml:"use_discovered_endpoints"`
+
+	// DiscoveryCacheTTL is how long the discovery document is cached. Zero
+	// uses the client default.
+	DiscoveryCacheTTL time.Duration `yaml:"discovery_cache_ttl"`
 }
 
 func (c *Config) CheckEndpoint() (*url.URL, error) {
@@ -36,14 +107,36 @@ func (c *Config) CheckEndpoint() (*url.URL, error) {
 	return uri, nil
 }
 
+func (c *Config) CheckSigningAlgorithms() error {
+	for _, alg := range c.AllowedSigningAlgorithms {
+		if !supportedSigningAlgorithms[alg] {
+			return fmt.Errorf("Unsupported signing algorithm: %s", alg)
+		}
+	}
+	return nil
+}
+
 func (c *Config) CheckCredentials() error {
 
 	if c.ClientName == "" {
 		return errors.New("OAuth Client ID cannot be empty")
 	}
 
-	if c.ClientSecret == "" {
-		return errors.New("OAuth Client Secret cannot be empty")
+	switch c.ClientAuthMethod {
+	case "", ClientAuthSecretBasic:
+		if c.ClientSecret == "" {
+			return errors.New("OAuth Client Secret cannot be empty")
+		}
+	case ClientAuthPrivateKeyJWT:
+		if c.ClientAssertionKey == "" {
+			return errors.New("OAuth Client assertion key cannot be empty")
+		}
+	case ClientAuthTLS:
+		if c.ClientCert == "" || c.ClientKey == "" {
+			return errors.New("OAuth Client certificate and key cannot be empty")
+		}
+	default:
+		return fmt.Errorf("Unsupported client authentication method: %s", c.ClientAuthMethod)
 	}
 
 	return nil
@@ -22,7 +22,6 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/onsi/gomega/gbytes"
 	"github.com/onsi/gomega/ghttp"
 )
 
@@ -127,12 +126,12 @@ var _ = Describe("DecodeToken", func() {
 				Expect(err).NotTo(HaveOccurred())
 
 				server.AppendHandlers(
-					getSuccessKeyFetchHandler(ValidPemPublicKey),
+					getSuccessKeySetFetchHandler(ValidPemPublicKey),
 					ghttp.CombineHandlers(
 						ghttp.VerifyRequest("GET", OpenIDConfigEndpoint),
 						ghttp.RespondWith(http.StatusOK, fmt.Sprintf("{\"issuer\":\"https://uaa.domain.com\"}")),
 					),
-					getSuccessKeyFetchHandler(ValidPemPublicKey),
+					getSuccessKeySetFetchHandler(ValidPemPublicKey),
 				)
 			})
 
@@ -164,7 +163,7 @@ var _ = Describe("DecodeToken", func() {
 		Context("when a token is not valid", func() {
 			BeforeEach(func() {
 				server.AppendHandlers(
-					getSuccessKeyFetchHandler(ValidPemPublicKey),
+					getSuccessKeySetFetchHandler(ValidPemPublicKey),
 				)
 			})
 
@@ -214,7 +213,7 @@ var _ = Describe("DecodeToken", func() {
 			Context("uaa returns token key", func() {
 				BeforeEach(func() {
 					server.AppendHandlers(
-						getSuccessKeyFetchHandler(ValidPemPublicKey),
+						getSuccessKeySetFetchHandler(ValidPemPublicKey),
 						ghttp.CombineHandlers(
 							ghttp.VerifyRequest("GET", OpenIDConfigEndpoint),
 							ghttp.RespondWith(http.StatusOK, `{"issuer":"https://uaa.domain.com"}`),
@@ -253,12 +252,12 @@ var _ = Describe("DecodeToken", func() {
 			Context("uaa returns a verification key", func() {
 				BeforeEach(func() {
 					server.AppendHandlers(
-						getSuccessKeyFetchHandler(ValidPemPublicKey),
+						getSuccessKeySetFetchHandler(ValidPemPublicKey),
 						ghttp.CombineHandlers(
 							ghttp.VerifyRequest("GET", OpenIDConfigEndpoint),
 							ghttp.RespondWith(http.StatusOK, `{"issuer":"https://uaa.domain.com"}`),
 						),
-						getSuccessKeyFetchHandler(ValidPemPublicKey),
+						getSuccessKeySetFetchHandler(ValidPemPublicKey),
 					)
 				})
 				It("refreshes the key and returns an invalid signature error", func() {
@@ -273,13 +272,13 @@ var _ = Describe("DecodeToken", func() {
 			Context("when uaa returns an error", func() {
 				BeforeEach(func() {
 					server.AppendHandlers(
-						getSuccessKeyFetchHandler(ValidPemPublicKey),
+						getSuccessKeySetFetchHandler(ValidPemPublicKey),
 						ghttp.CombineHandlers(
 							ghttp.VerifyRequest("GET", OpenIDConfigEndpoint),
 							ghttp.RespondWith(http.StatusOK, `{"issuer":"https://uaa.domain.com"}`),
 						),
 						ghttp.CombineHandlers(
-							ghttp.VerifyRequest("GET", TokenKeyEndpoint),
+							ghttp.VerifyRequest("GET", TokenKeysEndpoint),
 							ghttp.RespondWith(http.StatusGatewayTimeout, "booom"),
 						),
 					)
@@ -337,12 +336,12 @@ var _ = Describe("DecodeToken", func() {
 			Context("when a successful fetch happens", func() {
 				BeforeEach(func() {
 					server.AppendHandlers(
-						getSuccessKeyFetchHandler(InvalidPemPublicKey),
+						getSuccessKeySetFetchHandler(InvalidPemPublicKey),
 						ghttp.CombineHandlers(
 							ghttp.VerifyRequest("GET", OpenIDConfigEndpoint),
 							ghttp.RespondWith(http.StatusOK, `{"issuer":"https://uaa.domain.com"}`),
 						),
-						getSuccessKeyFetchHandler(ValidPemPublicKey),
+						getSuccessKeySetFetchHandler(ValidPemPublicKey),
 					)
 				})
 
@@ -361,8 +360,8 @@ var _ = Describe("DecodeToken", func() {
 								ghttp.VerifyRequest("GET", OpenIDConfigEndpoint),
 								ghttp.RespondWith(http.StatusOK, `{"issuer":"https://uaa.domain.com"}`),
 							),
-							getSuccessKeyFetchHandler(ValidPemPublicKey),
-							getSuccessKeyFetchHandler(ValidPemPublicKey),
+							getSuccessKeySetFetchHandler(ValidPemPublicKey),
+							getSuccessKeySetFetchHandler(ValidPemPublicKey),
 						)
 					})
 
@@ -384,46 +383,22 @@ var _ = Describe("DecodeToken", func() {
 					})
 				})
 
-				Context("when new key applies to only one client and not others", func() {
-					var (
-						keyChannel      chan string
-						expectErrorChan chan bool
-					)
-
+				Context("when uaa fails while the keys are first fetched", func() {
 					BeforeEach(func() {
-						keyChannel = make(chan string)
-						expectErrorChan = make(chan bool)
-
-						successHandler := func(w http.ResponseWriter, req *http.Request) {
-							key := <-keyChannel
-							w.Write([]byte(fmt.Sprintf("{\"alg\":\"alg\", \"value\": \"%s\" }", key)))
-						}
-
-						failureHandler := func(w http.ResponseWriter, req *http.Request) {
-							w.WriteHeader(http.StatusInternalServerError)
-							w.Write([]byte(""))
-						}
-
 						server.AppendHandlers(
 							ghttp.CombineHandlers(
 								ghttp.VerifyRequest("GET", OpenIDConfigEndpoint),
 								ghttp.RespondWith(http.StatusOK, `{"issuer":"https://uaa.domain.com"}`),
 							),
 							ghttp.CombineHandlers(
-								failureHandler,
-							),
-							ghttp.CombineHandlers(
-								successHandler,
+								ghttp.VerifyRequest("GET", TokenKeysEndpoint),
+								ghttp.RespondWith(http.StatusInternalServerError, ""),
 							),
+							getSuccessKeySetFetchHandler(ValidPemPublicKey),
 						)
 					})
 
-					AfterEach(func() {
-						close(keyChannel)
-						close(expectErrorChan)
-					})
-
-					It("fetches new key and validates the token", func() {
+					It("fetches the keys once and backs off before fetching again", func() {
 						wg := sync.WaitGroup{}
 						_, err := client.FetchIssuer()
 						Expect(err).NotTo(HaveOccurred())
@@ -433,26 +408,17 @@ var _ = Describe("DecodeToken", func() {
 								defer GinkgoRecover()
 								defer wg.Done()
 								err := client.DecodeToken(signedKey, "route.advertise")
-								select {
-								case fail := <-expectErrorChan:
-									if fail {
-										Expect(err).To(HaveOccurred())
-										Expect(err.Error()).To(ContainSubstring("http error"))
-									} else {
-										Expect(err).NotTo(HaveOccurred())
-									}
-								}
+								Expect(err).To(HaveOccurred())
+								Expect(err.Error()).To(ContainSubstring("http error"))
 							}(&wg)
 						}
-						// Error expected due to internal server error from UAA
-						expectErrorChan <- true
-
-						keyChannel <- ValidPemPublicKey
+						wg.Wait()
+						Expect(len(server.ReceivedRequests())).To(Equal(2))
 
-						// retrieved valid pem key from UAA, no error expected
-						expectErrorChan <- false
+						clock.Increment(uaa_go_client.DefaultMinKeyRefetchInterval)
 
-						wg.Wait()
+						err = client.DecodeToken(signedKey, "route.advertise")
+						Expect(err).NotTo(HaveOccurred())
 						Expect(len(server.ReceivedRequests())).To(Equal(3))
 					})
 				})
@@ -465,7 +431,7 @@ var _ = Describe("DecodeToken", func() {
 
 				token.Claims = customClaims{
 					StandardClaims: jwt.StandardClaims{
-						ExpiresAt: time.Now().Unix() - 5,
+						ExpiresAt: time.Now().Add(-config.DefaultClockSkewLeeway).Unix() - 5,
 						Issuer:    "https://uaa.domain.com",
 					},
 				}
@@ -475,7 +441,7 @@ var _ = Describe("DecodeToken", func() {
 
 				signedKey = "bearer " + signedKey
 				server.AppendHandlers(
-					getSuccessKeyFetchHandler(ValidPemPublicKey),
+					getSuccessKeySetFetchHandler(ValidPemPublicKey),
 					ghttp.CombineHandlers(
 						ghttp.VerifyRequest("GET", OpenIDConfigEndpoint),
 						ghttp.RespondWith(http.StatusOK, `{"issuer":"https://uaa.domain.com"}`),
@@ -507,7 +473,7 @@ var _ = Describe("DecodeToken", func() {
 
 				signedKey = "bearer " + signedKey
 				server.AppendHandlers(
-					getSuccessKeyFetchHandler(ValidPemPublicKey),
+					getSuccessKeySetFetchHandler(ValidPemPublicKey),
 					ghttp.CombineHandlers(
 						ghttp.VerifyRequest("GET", OpenIDConfigEndpoint),
 						ghttp.RespondWith(http.StatusOK, `{"issuer":"https://uaa.domain.com"}`),
@@ -515,10 +481,24 @@ var _ = Describe("DecodeToken", func() {
 				)
 			})
 
-			It("logs the error but successfully validates", func() {
+			It("returns an error if the token was issued in the future", func() {
 				err := client.DecodeToken(signedKey, "route.foo")
-				Expect(err).NotTo(HaveOccurred())
-				Expect(logger).To(gbytes.Say("decode-token-ignoring-issued-at-validation"))
+				Expect(err).To(HaveOccurred())
+				verifyErrorType(err, jwt.ValidationErrorIssuedAt, "Token used before issued")
+			})
+
+			Context("when the issue time is within the clock skew leeway", func() {
+				BeforeEach(func() {
+					var err error
+					cfg.ClockSkewLeeway = 2 * time.Minute
+					client, err = uaa_go_client.NewClient(logger, cfg, clock)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("successfully validates", func() {
+					err := client.DecodeToken(signedKey, "route.foo")
+					Expect(err).NotTo(HaveOccurred())
+				})
 			})
 		})
 
@@ -539,7 +519,7 @@ var _ = Describe("DecodeToken", func() {
 
 				signedKey = "bearer " + signedKey
 				server.AppendHandlers(
-					getSuccessKeyFetchHandler(ValidPemPublicKey),
+					getSuccessKeySetFetchHandler(ValidPemPublicKey),
 					ghttp.CombineHandlers(
 						ghttp.VerifyRequest("GET", OpenIDConfigEndpoint),
 						ghttp.RespondWith(http.StatusOK, `{"issuer":"https://uaa.domain.com"}`),
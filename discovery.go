@@ -0,0 +1,196 @@
+package uaa_go_client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	trace "code.cloudfoundry.org/trace-logger"
+
+	"code.cloudfoundry.org/uaa-go-client/schema"
+)
+
+// DefaultDiscoveryCacheTTL is used when config.DiscoveryCacheTTL is not set.
+const DefaultDiscoveryCacheTTL = time.Hour
+
+// discoveryRetryInterval is how long endpoint falls back to the default
+// paths without asking UAA again after the discovery document could not be
+// fetched.
+const discoveryRetryInterval = 30 * time.Second
+
+type cachedOpenIDConfiguration struct {
+	configuration *schema.OpenIDConfiguration
+	expiresAt     time.Time
+}
+
+// FetchOpenIDConfiguration returns UAA's discovery document, fetching it
+// when forceUpdate is set or the cached copy has expired.
+func (u *UaaClient) FetchOpenIDConfiguration(forceUpdate bool) (*schema.OpenIDConfiguration, error) {
+	logger := u.logger.Session("uaa-client")
+
+	if !forceUpdate {
+		if discovery := u.getOpenIDConfiguration(); discovery != nil {
+			return discovery, nil
+		}
+	}
+
+	fetchOpenIdURL := fmt.Sprintf("%s/.well-known/openid-configuration", u.config.UaaEndpoint)
+	logger.Info("started-fetching-openId-metadata", lager.Data{"endpoint": fetchOpenIdURL})
+
+	request, err := http.NewRequest("GET", fetchOpenIdURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	trace.DumpRequest(request)
+	resp, err := u.do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	trace.DumpResponse(resp)
+	logger.Info("finished-fetching-openId-metatdata", lager.Data{"status-code": resp.StatusCode})
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.New(fmt.Sprintf("status code: %d, body: %s", resp.StatusCode, body))
+	}
+
+	discovery := &schema.OpenIDConfiguration{}
+	err = json.Unmarshal(body, discovery)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("successfully-received-issuer")
+	u.setOpenIDConfiguration(discovery)
+	if u.config.Issuer == "" {
+		u.updateIssuer(discovery.Issuer)
+	}
+	return discovery, nil
+}
+
+func (u *UaaClient) getOpenIDConfiguration() *schema.OpenIDConfiguration {
+	u.rwlock.RLock()
+	defer u.rwlock.RUnlock()
+
+	if u.discovery == nil || !u.clock.Now().Before(u.discovery.expiresAt) {
+		return nil
+	}
+	return u.discovery.configuration
+}
+
+func (u *UaaClient) setOpenIDConfiguration(discovery *schema.OpenIDConfiguration) {
+	ttl := u.config.DiscoveryCacheTTL
+	if ttl <= 0 {
+		ttl = DefaultDiscoveryCacheTTL
+	}
+
+	u.rwlock.Lock()
+	defer u.rwlock.Unlock()
+	u.discovery = &cachedOpenIDConfiguration{
+		configuration: discovery,
+		expiresAt:     u.clock.Now().Add(ttl),
+	}
+}
+
+func (u *UaaClient) discoveryBackingOff() bool {
+	u.rwlock.RLock()
+	defer u.rwlock.RUnlock()
+	return u.clock.Now().Before(u.discoveryRetry)
+}
+
+func (u *UaaClient) backOffDiscovery() {
+	u.rwlock.Lock()
+	defer u.rwlock.Unlock()
+	u.discoveryRetry = u.clock.Now().Add(discoveryRetryInterval)
+}
+
+// endpoint returns the URL of a UAA endpoint. With UseDiscoveredEndpoints
+// set, the URL advertised in the discovery document is preferred over the
+// default path under UaaEndpoint. When the document cannot be fetched the
+// default path is used, and discovery is not retried for
+// discoveryRetryInterval.
+func (u *UaaClient) endpoint(logger lager.Logger, path string, advertised func(*schema.OpenIDConfiguration) string) string {
+	defaultURL := u.config.UaaEndpoint + path
+	if !u.config.UseDiscoveredEndpoints {
+		return defaultURL
+	}
+
+	if u.discoveryBackingOff() {
+		return defaultURL
+	}
+
+	discovery, err := u.FetchOpenIDConfiguration(false)
+	if err != nil {
+		logger.Error("fetch-openid-configuration-failed", err, lager.Data{"endpoint": defaultURL})
+		u.backOffDiscovery()
+		return defaultURL
+	}
+
+	if advertisedURL := advertised(discovery); advertisedURL != "" {
+		return advertisedURL
+	}
+	return defaultURL
+}
+
+func (u *UaaClient) tokenURL(logger lager.Logger) string {
+	return u.endpoint(logger, "/oauth/token", func(d *schema.OpenIDConfiguration) string {
+		return d.TokenEndpoint
+	})
+}
+
+func (u *UaaClient) authorizeURL(logger lager.Logger) string {
+	return u.endpoint(logger, "/oauth/authorize", func(d *schema.OpenIDConfiguration) string {
+		return d.AuthorizationEndpoint
+	})
+}
+
+func (u *UaaClient) tokenKeysURL(logger lager.Logger) string {
+	return u.endpoint(logger, "/token_keys", func(d *schema.OpenIDConfiguration) string {
+		return d.JwksUri
+	})
+}
+
+// tokenKeyURL is not advertised by UAA, so it is derived from jwks_uri which
+// UAA serves next to it.
+func (u *UaaClient) tokenKeyURL(logger lager.Logger) string {
+	return u.endpoint(logger, "/token_key", func(d *schema.OpenIDConfiguration) string {
+		if strings.HasSuffix(d.JwksUri, "/token_keys") {
+			return strings.TrimSuffix(d.JwksUri, "s")
+		}
+		return ""
+	})
+}
+
+func (u *UaaClient) introspectURL(logger lager.Logger) string {
+	return u.endpoint(logger, "/introspect", func(d *schema.OpenIDConfiguration) string {
+		return d.IntrospectionEndpoint
+	})
+}
+
+// checkTokenURL is not advertised by UAA, so it is derived from the token
+// endpoint which UAA serves next to it.
+func (u *UaaClient) checkTokenURL(logger lager.Logger) string {
+	return u.endpoint(logger, "/check_token", func(d *schema.OpenIDConfiguration) string {
+		if strings.HasSuffix(d.TokenEndpoint, "/oauth/token") {
+			return strings.TrimSuffix(d.TokenEndpoint, "/oauth/token") + "/check_token"
+		}
+		return ""
+	})
+}
+
+func (u *UaaClient) userInfoURL(logger lager.Logger) string {
+	return u.endpoint(logger, "/userinfo", func(d *schema.OpenIDConfiguration) string {
+		return d.UserinfoEndpoint
+	})
+}
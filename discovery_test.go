@@ -0,0 +1,358 @@
+package uaa_go_client_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/config"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("OpenID discovery", func() {
+	var (
+		client    uaa_go_client.Client
+		discovery *schema.OpenIDConfiguration
+	)
+
+	discoveryHandler := func() http.HandlerFunc {
+		return ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", OpenIDConfigEndpoint),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, discovery),
+		)
+	}
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			MaxNumberOfRetries:    DefaultMaxNumberOfRetries,
+			RetryInterval:         DefaultRetryInterval,
+			ExpirationBufferInSec: DefaultExpirationBufferTime,
+			RequestTimeout:        DefaultRequestTimeout,
+		}
+		server = ghttp.NewServer()
+
+		url, err := url.Parse(server.URL())
+		Expect(err).ToNot(HaveOccurred())
+		cfg.UaaEndpoint = "http://" + url.Host
+
+		cfg.ClientName = "client-name"
+		cfg.ClientSecret = "client-secret"
+		clock = fakeclock.NewFakeClock(time.Now())
+		logger = lagertest.NewTestLogger("test")
+
+		proxied := server.URL() + "/uaa"
+		discovery = &schema.OpenIDConfiguration{
+			Issuer:                            proxied + "/oauth/token",
+			AuthorizationEndpoint:             proxied + "/oauth/authorize",
+			TokenEndpoint:                     proxied + "/oauth/token",
+			UserinfoEndpoint:                  proxied + "/userinfo",
+			JwksUri:                           proxied + "/token_keys",
+			ScopesSupported:                   []string{"openid", "profile"},
+			TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "private_key_jwt"},
+			CodeChallengeMethodsSupported:     []string{"S256", "plain"},
+		}
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("FetchOpenIDConfiguration", func() {
+		It("parses the discovery document", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", OpenIDConfigEndpoint),
+					ghttp.RespondWith(http.StatusOK, `{
+						"issuer": "https://uaa.domain.com/oauth/token",
+						"authorization_endpoint": "https://login.domain.com/oauth/authorize",
+						"token_endpoint": "https://uaa.domain.com/oauth/token",
+						"userinfo_endpoint": "https://uaa.domain.com/userinfo",
+						"jwks_uri": "https://uaa.domain.com/token_keys",
+						"end_session_endpoint": "https://uaa.domain.com/logout.do",
+						"scopes_supported": ["openid", "profile", "email"],
+						"response_types_supported": ["code", "id_token"],
+						"subject_types_supported": ["public"],
+						"token_endpoint_auth_methods_supported": ["client_secret_basic", "client_secret_post"],
+						"token_endpoint_auth_signing_alg_values_supported": ["RS256"],
+						"id_token_signing_alg_values_supported": ["RS256"],
+						"claim_types_supported": ["normal"],
+						"claims_supported": ["sub", "email"],
+						"claims_parameter_supported": false,
+						"code_challenge_methods_supported": ["S256", "plain"],
+						"ui_locales_supported": ["en-US"],
+						"service_documentation": "http://docs.cloudfoundry.org/api/uaa/"
+					}`),
+				),
+			)
+
+			discovery, err := client.FetchOpenIDConfiguration(false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(discovery).To(Equal(&schema.OpenIDConfiguration{
+				Issuer:                                "https://uaa.domain.com/oauth/token",
+				AuthorizationEndpoint:                 "https://login.domain.com/oauth/authorize",
+				TokenEndpoint:                         "https://uaa.domain.com/oauth/token",
+				UserinfoEndpoint:                      "https://uaa.domain.com/userinfo",
+				JwksUri:                               "https://uaa.domain.com/token_keys",
+				EndSessionEndpoint:                    "https://uaa.domain.com/logout.do",
+				ScopesSupported:                       []string{"openid", "profile", "email"},
+				ResponseTypesSupported:                []string{"code", "id_token"},
+				SubjectTypesSupported:                 []string{"public"},
+				TokenEndpointAuthMethodsSupported:     []string{"client_secret_basic", "client_secret_post"},
+				TokenEndpointAuthSigningAlgsSupported: []string{"RS256"},
+				IdTokenSigningAlgValuesSupported:      []string{"RS256"},
+				ClaimTypesSupported:                   []string{"normal"},
+				ClaimsSupported:                       []string{"sub", "email"},
+				CodeChallengeMethodsSupported:         []string{"S256", "plain"},
+				UILocalesSupported:                    []string{"en-US"},
+				ServiceDocumentation:                  "http://docs.cloudfoundry.org/api/uaa/",
+			}))
+		})
+
+		It("caches the discovery document until it expires", func() {
+			server.AppendHandlers(discoveryHandler(), discoveryHandler())
+
+			_, err := client.FetchOpenIDConfiguration(false)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.FetchOpenIDConfiguration(false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+
+			clock.Increment(uaa_go_client.DefaultDiscoveryCacheTTL)
+
+			_, err = client.FetchOpenIDConfiguration(false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		Context("when a cache TTL is configured", func() {
+			BeforeEach(func() {
+				cfg.DiscoveryCacheTTL = time.Minute
+			})
+
+			It("refetches after the TTL", func() {
+				server.AppendHandlers(discoveryHandler(), discoveryHandler())
+
+				_, err := client.FetchOpenIDConfiguration(false)
+				Expect(err).NotTo(HaveOccurred())
+
+				clock.Increment(59 * time.Second)
+				_, err = client.FetchOpenIDConfiguration(false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(server.ReceivedRequests()).To(HaveLen(1))
+
+				clock.Increment(time.Second)
+				_, err = client.FetchOpenIDConfiguration(false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(server.ReceivedRequests()).To(HaveLen(2))
+			})
+		})
+
+		It("refetches when forced", func() {
+			server.AppendHandlers(discoveryHandler(), discoveryHandler())
+
+			_, err := client.FetchOpenIDConfiguration(false)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.FetchOpenIDConfiguration(true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("returns an error when UAA does not serve the document", func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusNotFound, "not found"))
+
+			_, err := client.FetchOpenIDConfiguration(false)
+			Expect(err).To(MatchError("status code: 404, body: not found"))
+		})
+
+		It("provides the issuer for FetchIssuer", func() {
+			server.AppendHandlers(discoveryHandler())
+
+			issuer, err := client.FetchIssuer()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(issuer).To(Equal(discovery.Issuer))
+
+			cached, err := client.FetchOpenIDConfiguration(false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cached.Issuer).To(Equal(discovery.Issuer))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("uses the cached discovery document for FetchIssuer", func() {
+			server.AppendHandlers(discoveryHandler())
+
+			_, err := client.FetchOpenIDConfiguration(false)
+			Expect(err).NotTo(HaveOccurred())
+
+			issuer, err := client.FetchIssuer()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(issuer).To(Equal(discovery.Issuer))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	Context("when discovered endpoints are used", func() {
+		BeforeEach(func() {
+			cfg.UseDiscoveredEndpoints = true
+		})
+
+		It("fetches tokens from the advertised token endpoint", func() {
+			server.AppendHandlers(
+				discoveryHandler(),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/uaa/oauth/token"),
+					ghttp.VerifyBasicAuth("client-name", "client-secret"),
+					verifyBody("grant_type=client_credentials"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, &schema.Token{AccessToken: "the token", ExpiresIn: 3600}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/uaa/oauth/token"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, &schema.Token{AccessToken: "the user token"}),
+				),
+			)
+
+			token, err := client.FetchToken(true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.AccessToken).To(Equal("the token"))
+
+			token, err = client.FetchUserToken("user", "password")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.AccessToken).To(Equal("the user token"))
+
+			Expect(server.ReceivedRequests()).To(HaveLen(3))
+		})
+
+		It("fetches keys from the advertised JWKS endpoint", func() {
+			server.AppendHandlers(
+				discoveryHandler(),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/uaa/token_keys"),
+					ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"keys":[{"alg":"alg","value":"%s"}]}`, ValidPemPublicKey)),
+				),
+			)
+
+			_, err := client.FetchKeySet()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("falls back to the legacy key endpoint next to the JWKS endpoint", func() {
+			server.AppendHandlers(
+				discoveryHandler(),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/uaa/token_keys"),
+					ghttp.RespondWith(http.StatusNotFound, ""),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/uaa/token_key"),
+					ghttp.RespondWith(http.StatusOK, fmt.Sprintf(`{"alg":"alg","value":"%s"}`, ValidPemPublicKey)),
+				),
+			)
+
+			_, err := client.FetchKeySet()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("introspects tokens at the advertised endpoint", func() {
+			discovery.IntrospectionEndpoint = server.URL() + "/uaa/introspect"
+			server.AppendHandlers(
+				discoveryHandler(),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/uaa/introspect"),
+					ghttp.RespondWith(http.StatusOK, `{"active":false}`),
+				),
+			)
+
+			introspection, err := client.IntrospectToken("some-token")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(introspection.Active).To(BeFalse())
+		})
+
+		It("falls back to the check_token endpoint next to the token endpoint", func() {
+			server.AppendHandlers(
+				discoveryHandler(),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/introspect"),
+					ghttp.RespondWith(http.StatusNotFound, ""),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/uaa/check_token"),
+					ghttp.RespondWith(http.StatusBadRequest, `{"error":"invalid_token"}`),
+				),
+			)
+
+			introspection, err := client.IntrospectToken("some-token")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(introspection.Active).To(BeFalse())
+		})
+
+		It("builds authorize URLs from the advertised endpoint", func() {
+			server.AppendHandlers(discoveryHandler())
+
+			request, err := client.AuthorizationCodeURL("https://example.com/callback")
+			Expect(err).NotTo(HaveOccurred())
+
+			authorizeURL, err := url.Parse(request.URL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(authorizeURL.Path).To(Equal("/uaa/oauth/authorize"))
+			Expect(authorizeURL.Query().Get("client_id")).To(Equal("client-name"))
+		})
+
+		It("uses the default paths when the discovery document cannot be fetched", func() {
+			server.RouteToHandler("GET", OpenIDConfigEndpoint, ghttp.RespondWith(http.StatusInternalServerError, "error"))
+			server.AppendHandlers(
+				getOauthHandlerFunc(http.StatusOK, &schema.Token{AccessToken: "the token", ExpiresIn: 3600}),
+			)
+
+			token, err := client.FetchToken(true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.AccessToken).To(Equal("the token"))
+			Expect(logger).To(gbytes.Say("fetch-openid-configuration-failed"))
+		})
+
+		It("does not retry discovery for every request while it is failing", func() {
+			discoveryRequests := func() int {
+				count := 0
+				for _, request := range server.ReceivedRequests() {
+					if request.URL.Path == OpenIDConfigEndpoint {
+						count++
+					}
+				}
+				return count
+			}
+
+			server.RouteToHandler("GET", OpenIDConfigEndpoint, ghttp.RespondWith(http.StatusInternalServerError, "error"))
+			server.AppendHandlers(
+				getOauthHandlerFunc(http.StatusOK, &schema.Token{AccessToken: "the token", ExpiresIn: 3600}),
+				getOauthHandlerFunc(http.StatusOK, &schema.Token{AccessToken: "the token", ExpiresIn: 3600}),
+				getOauthHandlerFunc(http.StatusOK, &schema.Token{AccessToken: "the token", ExpiresIn: 3600}),
+			)
+
+			_, err := client.FetchToken(true)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.FetchToken(true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(discoveryRequests()).To(Equal(1))
+
+			clock.Increment(time.Minute)
+
+			_, err = client.FetchToken(true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(discoveryRequests()).To(Equal(2))
+		})
+	})
+})
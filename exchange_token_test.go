@@ -0,0 +1,125 @@
+package uaa_go_client_test
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/config"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("ExchangeToken", func() {
+	var (
+		client uaa_go_client.Client
+	)
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			MaxNumberOfRetries:    DefaultMaxNumberOfRetries,
+			RetryInterval:         DefaultRetryInterval,
+			ExpirationBufferInSec: DefaultExpirationBufferTime,
+			RequestTimeout:        DefaultRequestTimeout,
+		}
+		server = ghttp.NewServer()
+
+		url, err := url.Parse(server.URL())
+		Expect(err).ToNot(HaveOccurred())
+		cfg.UaaEndpoint = "http://" + url.Host
+
+		cfg.ClientName = "client-name"
+		cfg.ClientSecret = "client-secret"
+		clock = fakeclock.NewFakeClock(time.Now())
+		logger = lagertest.NewTestLogger("test")
+
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("when only a subject token is given", func() {
+		It("defaults the subject token type to access token", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/oauth/token"),
+					ghttp.VerifyBasicAuth("client-name", "client-secret"),
+					ghttp.VerifyContentType("application/x-www-form-urlencoded; charset=UTF-8"),
+					verifyBody("grant_type=urn%3Aietf%3Aparams%3Aoauth%3Agrant-type%3Atoken-exchange&subject_token=user.jwt&subject_token_type=urn%3Aietf%3Aparams%3Aoauth%3Atoken-type%3Aaccess_token"),
+					ghttp.RespondWith(http.StatusOK, `{
+						"access_token": "exchanged token",
+						"token_type": "bearer",
+						"expires_in": 3600,
+						"issued_token_type": "urn:ietf:params:oauth:token-type:access_token"
+					}`),
+				),
+			)
+
+			token, err := client.ExchangeToken(&schema.TokenExchangeRequest{SubjectToken: "user.jwt"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.AccessToken).To(Equal("exchanged token"))
+			Expect(token.IssuedTokenType).To(Equal(schema.TokenTypeAccessToken))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	Context("when actor token, audience and scopes are given", func() {
+		It("sends all the exchange parameters", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					verifyBody("actor_token=service.jwt&actor_token_type=urn%3Aietf%3Aparams%3Aoauth%3Atoken-type%3Ajwt&audience=cloud_controller&audience=routing&grant_type=urn%3Aietf%3Aparams%3Aoauth%3Agrant-type%3Atoken-exchange&requested_token_type=urn%3Aietf%3Aparams%3Aoauth%3Atoken-type%3Ajwt&scope=cloud_controller.read&subject_token=user.id.token&subject_token_type=urn%3Aietf%3Aparams%3Aoauth%3Atoken-type%3Aid_token"),
+					ghttp.RespondWith(http.StatusOK, `{
+						"access_token": "exchanged token",
+						"issued_token_type": "urn:ietf:params:oauth:token-type:jwt"
+					}`),
+				),
+			)
+
+			token, err := client.ExchangeToken(&schema.TokenExchangeRequest{
+				SubjectToken:       "user.id.token",
+				SubjectTokenType:   schema.TokenTypeIDToken,
+				ActorToken:         "service.jwt",
+				ActorTokenType:     schema.TokenTypeJWT,
+				RequestedTokenType: schema.TokenTypeJWT,
+				Scopes:             []string{"cloud_controller.read"},
+				Audience:           []string{"cloud_controller", "routing"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.IssuedTokenType).To(Equal(schema.TokenTypeJWT))
+		})
+	})
+
+	Context("when the subject token is missing", func() {
+		It("returns an error without contacting UAA", func() {
+			_, err := client.ExchangeToken(&schema.TokenExchangeRequest{ActorToken: "service.jwt"})
+			Expect(err).To(MatchError("Subject token cannot be empty"))
+
+			_, err = client.ExchangeToken(nil)
+			Expect(err).To(MatchError("Subject token cannot be empty"))
+			Expect(server.ReceivedRequests()).To(HaveLen(0))
+		})
+	})
+
+	Context("when UAA rejects the exchange", func() {
+		It("returns an error and doesn't retry", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusForbidden, `{"error":"unauthorized_client"}`),
+			)
+
+			_, err := client.ExchangeToken(&schema.TokenExchangeRequest{SubjectToken: "user.jwt"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(`status code: 403, body: {"error":"unauthorized_client"}`))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+})
@@ -9,6 +9,24 @@ import (
 )
 
 type FakeClient struct {
+	AuthorizationCodeURLStub        func(string, ...uaa_go_client.TokenOption) (*schema.AuthorizationRequest, error)
+	authorizationCodeURLMutex       sync.RWMutex
+	authorizationCodeURLArgsForCall []struct {
+		arg1 string
+		arg2 []uaa_go_client.TokenOption
+	}
+	authorizationCodeURLReturns struct {
+		result1 *schema.AuthorizationRequest
+		result2 error
+	}
+	authorizationCodeURLReturnsOnCall map[int]struct {
+		result1 *schema.AuthorizationRequest
+		result2 error
+	}
+	CloseStub        func()
+	closeMutex       sync.RWMutex
+	closeArgsForCall []struct {
+	}
 	DecodeTokenStub        func(string, ...string) error
 	decodeTokenMutex       sync.RWMutex
 	decodeTokenArgsForCall []struct {
@@ -21,6 +39,34 @@ type FakeClient struct {
 	decodeTokenReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ExchangeAuthorizationCodeStub        func(string, string, string) (*schema.Token, error)
+	exchangeAuthorizationCodeMutex       sync.RWMutex
+	exchangeAuthorizationCodeArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	exchangeAuthorizationCodeReturns struct {
+		result1 *schema.Token
+		result2 error
+	}
+	exchangeAuthorizationCodeReturnsOnCall map[int]struct {
+		result1 *schema.Token
+		result2 error
+	}
+	ExchangeTokenStub        func(*schema.TokenExchangeRequest) (*schema.Token, error)
+	exchangeTokenMutex       sync.RWMutex
+	exchangeTokenArgsForCall []struct {
+		arg1 *schema.TokenExchangeRequest
+	}
+	exchangeTokenReturns struct {
+		result1 *schema.Token
+		result2 error
+	}
+	exchangeTokenReturnsOnCall map[int]struct {
+		result1 *schema.Token
+		result2 error
+	}
 	FetchIssuerStub        func() (string, error)
 	fetchIssuerMutex       sync.RWMutex
 	fetchIssuerArgsForCall []struct {
@@ -45,6 +91,45 @@ type FakeClient struct {
 		result1 string
 		result2 error
 	}
+	FetchKeySetStub        func() (*schema.JSONWebKeySet, error)
+	fetchKeySetMutex       sync.RWMutex
+	fetchKeySetArgsForCall []struct {
+	}
+	fetchKeySetReturns struct {
+		result1 *schema.JSONWebKeySet
+		result2 error
+	}
+	fetchKeySetReturnsOnCall map[int]struct {
+		result1 *schema.JSONWebKeySet
+		result2 error
+	}
+	FetchOpenIDConfigurationStub        func(bool) (*schema.OpenIDConfiguration, error)
+	fetchOpenIDConfigurationMutex       sync.RWMutex
+	fetchOpenIDConfigurationArgsForCall []struct {
+		arg1 bool
+	}
+	fetchOpenIDConfigurationReturns struct {
+		result1 *schema.OpenIDConfiguration
+		result2 error
+	}
+	fetchOpenIDConfigurationReturnsOnCall map[int]struct {
+		result1 *schema.OpenIDConfiguration
+		result2 error
+	}
+	FetchPasscodeTokenStub        func(string, ...uaa_go_client.TokenOption) (*schema.Token, error)
+	fetchPasscodeTokenMutex       sync.RWMutex
+	fetchPasscodeTokenArgsForCall []struct {
+		arg1 string
+		arg2 []uaa_go_client.TokenOption
+	}
+	fetchPasscodeTokenReturns struct {
+		result1 *schema.Token
+		result2 error
+	}
+	fetchPasscodeTokenReturnsOnCall map[int]struct {
+		result1 *schema.Token
+		result2 error
+	}
 	FetchTokenStub        func(bool) (*schema.Token, error)
 	fetchTokenMutex       sync.RWMutex
 	fetchTokenArgsForCall []struct {
@@ -58,6 +143,114 @@ type FakeClient struct {
 		result1 *schema.Token
 		result2 error
 	}
+	FetchTokenForScopesStub        func([]string, bool) (*schema.Token, error)
+	fetchTokenForScopesMutex       sync.RWMutex
+	fetchTokenForScopesArgsForCall []struct {
+		arg1 []string
+		arg2 bool
+	}
+	fetchTokenForScopesReturns struct {
+		result1 *schema.Token
+		result2 error
+	}
+	fetchTokenForScopesReturnsOnCall map[int]struct {
+		result1 *schema.Token
+		result2 error
+	}
+	FetchTokenWithAssertionStub        func(string, ...string) (*schema.Token, error)
+	fetchTokenWithAssertionMutex       sync.RWMutex
+	fetchTokenWithAssertionArgsForCall []struct {
+		arg1 string
+		arg2 []string
+	}
+	fetchTokenWithAssertionReturns struct {
+		result1 *schema.Token
+		result2 error
+	}
+	fetchTokenWithAssertionReturnsOnCall map[int]struct {
+		result1 *schema.Token
+		result2 error
+	}
+	FetchUserInfoStub        func(string) (*schema.UserInfo, error)
+	fetchUserInfoMutex       sync.RWMutex
+	fetchUserInfoArgsForCall []struct {
+		arg1 string
+	}
+	fetchUserInfoReturns struct {
+		result1 *schema.UserInfo
+		result2 error
+	}
+	fetchUserInfoReturnsOnCall map[int]struct {
+		result1 *schema.UserInfo
+		result2 error
+	}
+	FetchUserTokenStub        func(string, string, ...uaa_go_client.TokenOption) (*schema.Token, error)
+	fetchUserTokenMutex       sync.RWMutex
+	fetchUserTokenArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 []uaa_go_client.TokenOption
+	}
+	fetchUserTokenReturns struct {
+		result1 *schema.Token
+		result2 error
+	}
+	fetchUserTokenReturnsOnCall map[int]struct {
+		result1 *schema.Token
+		result2 error
+	}
+	FetchUserTokenForClientStub        func(string, string, ...string) (*schema.Token, error)
+	fetchUserTokenForClientMutex       sync.RWMutex
+	fetchUserTokenForClientArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 []string
+	}
+	fetchUserTokenForClientReturns struct {
+		result1 *schema.Token
+		result2 error
+	}
+	fetchUserTokenForClientReturnsOnCall map[int]struct {
+		result1 *schema.Token
+		result2 error
+	}
+	ForZoneStub        func(schema.IdentityZone) uaa_go_client.Client
+	forZoneMutex       sync.RWMutex
+	forZoneArgsForCall []struct {
+		arg1 schema.IdentityZone
+	}
+	forZoneReturns struct {
+		result1 uaa_go_client.Client
+	}
+	forZoneReturnsOnCall map[int]struct {
+		result1 uaa_go_client.Client
+	}
+	IntrospectTokenStub        func(string) (*schema.TokenIntrospection, error)
+	introspectTokenMutex       sync.RWMutex
+	introspectTokenArgsForCall []struct {
+		arg1 string
+	}
+	introspectTokenReturns struct {
+		result1 *schema.TokenIntrospection
+		result2 error
+	}
+	introspectTokenReturnsOnCall map[int]struct {
+		result1 *schema.TokenIntrospection
+		result2 error
+	}
+	RefreshTokenStub        func(string) (*schema.Token, error)
+	refreshTokenMutex       sync.RWMutex
+	refreshTokenArgsForCall []struct {
+		arg1 string
+	}
+	refreshTokenReturns struct {
+		result1 *schema.Token
+		result2 error
+	}
+	refreshTokenReturnsOnCall map[int]struct {
+		result1 *schema.Token
+		result2 error
+	}
 	RegisterOauthClientStub        func(*schema.OauthClient) (*schema.OauthClient, error)
 	registerOauthClientMutex       sync.RWMutex
 	registerOauthClientArgsForCall []struct {
@@ -71,10 +264,173 @@ type FakeClient struct {
 		result1 *schema.OauthClient
 		result2 error
 	}
+	RevokeClientTokensStub        func(string) error
+	revokeClientTokensMutex       sync.RWMutex
+	revokeClientTokensArgsForCall []struct {
+		arg1 string
+	}
+	revokeClientTokensReturns struct {
+		result1 error
+	}
+	revokeClientTokensReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RevokeTokenStub        func(string) error
+	revokeTokenMutex       sync.RWMutex
+	revokeTokenArgsForCall []struct {
+		arg1 string
+	}
+	revokeTokenReturns struct {
+		result1 error
+	}
+	revokeTokenReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RevokeUserClientTokensStub        func(string, string) error
+	revokeUserClientTokensMutex       sync.RWMutex
+	revokeUserClientTokensArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	revokeUserClientTokensReturns struct {
+		result1 error
+	}
+	revokeUserClientTokensReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RevokeUserTokensStub        func(string) error
+	revokeUserTokensMutex       sync.RWMutex
+	revokeUserTokensArgsForCall []struct {
+		arg1 string
+	}
+	revokeUserTokensReturns struct {
+		result1 error
+	}
+	revokeUserTokensReturnsOnCall map[int]struct {
+		result1 error
+	}
+	VerifyIDTokenStub        func(string, string, string) (*schema.IDTokenClaims, error)
+	verifyIDTokenMutex       sync.RWMutex
+	verifyIDTokenArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}
+	verifyIDTokenReturns struct {
+		result1 *schema.IDTokenClaims
+		result2 error
+	}
+	verifyIDTokenReturnsOnCall map[int]struct {
+		result1 *schema.IDTokenClaims
+		result2 error
+	}
+	VerifyTokenStub        func(string, ...uaa_go_client.VerifyOption) (*schema.Claims, error)
+	verifyTokenMutex       sync.RWMutex
+	verifyTokenArgsForCall []struct {
+		arg1 string
+		arg2 []uaa_go_client.VerifyOption
+	}
+	verifyTokenReturns struct {
+		result1 *schema.Claims
+		result2 error
+	}
+	verifyTokenReturnsOnCall map[int]struct {
+		result1 *schema.Claims
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeClient) AuthorizationCodeURL(arg1 string, arg2 ...uaa_go_client.TokenOption) (*schema.AuthorizationRequest, error) {
+	fake.authorizationCodeURLMutex.Lock()
+	ret, specificReturn := fake.authorizationCodeURLReturnsOnCall[len(fake.authorizationCodeURLArgsForCall)]
+	fake.authorizationCodeURLArgsForCall = append(fake.authorizationCodeURLArgsForCall, struct {
+		arg1 string
+		arg2 []uaa_go_client.TokenOption
+	}{arg1, arg2})
+	stub := fake.AuthorizationCodeURLStub
+	fakeReturns := fake.authorizationCodeURLReturns
+	fake.recordInvocation("AuthorizationCodeURL", []interface{}{arg1, arg2})
+	fake.authorizationCodeURLMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) AuthorizationCodeURLCallCount() int {
+	fake.authorizationCodeURLMutex.RLock()
+	defer fake.authorizationCodeURLMutex.RUnlock()
+	return len(fake.authorizationCodeURLArgsForCall)
+}
+
+func (fake *FakeClient) AuthorizationCodeURLCalls(stub func(string, ...uaa_go_client.TokenOption) (*schema.AuthorizationRequest, error)) {
+	fake.authorizationCodeURLMutex.Lock()
+	defer fake.authorizationCodeURLMutex.Unlock()
+	fake.AuthorizationCodeURLStub = stub
+}
+
+func (fake *FakeClient) AuthorizationCodeURLArgsForCall(i int) (string, []uaa_go_client.TokenOption) {
+	fake.authorizationCodeURLMutex.RLock()
+	defer fake.authorizationCodeURLMutex.RUnlock()
+	argsForCall := fake.authorizationCodeURLArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) AuthorizationCodeURLReturns(result1 *schema.AuthorizationRequest, result2 error) {
+	fake.authorizationCodeURLMutex.Lock()
+	defer fake.authorizationCodeURLMutex.Unlock()
+	fake.AuthorizationCodeURLStub = nil
+	fake.authorizationCodeURLReturns = struct {
+		result1 *schema.AuthorizationRequest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) AuthorizationCodeURLReturnsOnCall(i int, result1 *schema.AuthorizationRequest, result2 error) {
+	fake.authorizationCodeURLMutex.Lock()
+	defer fake.authorizationCodeURLMutex.Unlock()
+	fake.AuthorizationCodeURLStub = nil
+	if fake.authorizationCodeURLReturnsOnCall == nil {
+		fake.authorizationCodeURLReturnsOnCall = make(map[int]struct {
+			result1 *schema.AuthorizationRequest
+			result2 error
+		})
+	}
+	fake.authorizationCodeURLReturnsOnCall[i] = struct {
+		result1 *schema.AuthorizationRequest
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) Close() {
+	fake.closeMutex.Lock()
+	fake.closeArgsForCall = append(fake.closeArgsForCall, struct {
+	}{})
+	stub := fake.CloseStub
+	fake.recordInvocation("Close", []interface{}{})
+	fake.closeMutex.Unlock()
+	if stub != nil {
+		fake.CloseStub()
+	}
+}
+
+func (fake *FakeClient) CloseCallCount() int {
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
+	return len(fake.closeArgsForCall)
+}
+
+func (fake *FakeClient) CloseCalls(stub func()) {
+	fake.closeMutex.Lock()
+	defer fake.closeMutex.Unlock()
+	fake.CloseStub = stub
+}
+
 func (fake *FakeClient) DecodeToken(arg1 string, arg2 ...string) error {
 	fake.decodeTokenMutex.Lock()
 	ret, specificReturn := fake.decodeTokenReturnsOnCall[len(fake.decodeTokenArgsForCall)]
@@ -137,6 +493,136 @@ func (fake *FakeClient) DecodeTokenReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeClient) ExchangeAuthorizationCode(arg1 string, arg2 string, arg3 string) (*schema.Token, error) {
+	fake.exchangeAuthorizationCodeMutex.Lock()
+	ret, specificReturn := fake.exchangeAuthorizationCodeReturnsOnCall[len(fake.exchangeAuthorizationCodeArgsForCall)]
+	fake.exchangeAuthorizationCodeArgsForCall = append(fake.exchangeAuthorizationCodeArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.ExchangeAuthorizationCodeStub
+	fakeReturns := fake.exchangeAuthorizationCodeReturns
+	fake.recordInvocation("ExchangeAuthorizationCode", []interface{}{arg1, arg2, arg3})
+	fake.exchangeAuthorizationCodeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ExchangeAuthorizationCodeCallCount() int {
+	fake.exchangeAuthorizationCodeMutex.RLock()
+	defer fake.exchangeAuthorizationCodeMutex.RUnlock()
+	return len(fake.exchangeAuthorizationCodeArgsForCall)
+}
+
+func (fake *FakeClient) ExchangeAuthorizationCodeCalls(stub func(string, string, string) (*schema.Token, error)) {
+	fake.exchangeAuthorizationCodeMutex.Lock()
+	defer fake.exchangeAuthorizationCodeMutex.Unlock()
+	fake.ExchangeAuthorizationCodeStub = stub
+}
+
+func (fake *FakeClient) ExchangeAuthorizationCodeArgsForCall(i int) (string, string, string) {
+	fake.exchangeAuthorizationCodeMutex.RLock()
+	defer fake.exchangeAuthorizationCodeMutex.RUnlock()
+	argsForCall := fake.exchangeAuthorizationCodeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeClient) ExchangeAuthorizationCodeReturns(result1 *schema.Token, result2 error) {
+	fake.exchangeAuthorizationCodeMutex.Lock()
+	defer fake.exchangeAuthorizationCodeMutex.Unlock()
+	fake.ExchangeAuthorizationCodeStub = nil
+	fake.exchangeAuthorizationCodeReturns = struct {
+		result1 *schema.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ExchangeAuthorizationCodeReturnsOnCall(i int, result1 *schema.Token, result2 error) {
+	fake.exchangeAuthorizationCodeMutex.Lock()
+	defer fake.exchangeAuthorizationCodeMutex.Unlock()
+	fake.ExchangeAuthorizationCodeStub = nil
+	if fake.exchangeAuthorizationCodeReturnsOnCall == nil {
+		fake.exchangeAuthorizationCodeReturnsOnCall = make(map[int]struct {
+			result1 *schema.Token
+			result2 error
+		})
+	}
+	fake.exchangeAuthorizationCodeReturnsOnCall[i] = struct {
+		result1 *schema.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ExchangeToken(arg1 *schema.TokenExchangeRequest) (*schema.Token, error) {
+	fake.exchangeTokenMutex.Lock()
+	ret, specificReturn := fake.exchangeTokenReturnsOnCall[len(fake.exchangeTokenArgsForCall)]
+	fake.exchangeTokenArgsForCall = append(fake.exchangeTokenArgsForCall, struct {
+		arg1 *schema.TokenExchangeRequest
+	}{arg1})
+	stub := fake.ExchangeTokenStub
+	fakeReturns := fake.exchangeTokenReturns
+	fake.recordInvocation("ExchangeToken", []interface{}{arg1})
+	fake.exchangeTokenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) ExchangeTokenCallCount() int {
+	fake.exchangeTokenMutex.RLock()
+	defer fake.exchangeTokenMutex.RUnlock()
+	return len(fake.exchangeTokenArgsForCall)
+}
+
+func (fake *FakeClient) ExchangeTokenCalls(stub func(*schema.TokenExchangeRequest) (*schema.Token, error)) {
+	fake.exchangeTokenMutex.Lock()
+	defer fake.exchangeTokenMutex.Unlock()
+	fake.ExchangeTokenStub = stub
+}
+
+func (fake *FakeClient) ExchangeTokenArgsForCall(i int) *schema.TokenExchangeRequest {
+	fake.exchangeTokenMutex.RLock()
+	defer fake.exchangeTokenMutex.RUnlock()
+	argsForCall := fake.exchangeTokenArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ExchangeTokenReturns(result1 *schema.Token, result2 error) {
+	fake.exchangeTokenMutex.Lock()
+	defer fake.exchangeTokenMutex.Unlock()
+	fake.ExchangeTokenStub = nil
+	fake.exchangeTokenReturns = struct {
+		result1 *schema.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) ExchangeTokenReturnsOnCall(i int, result1 *schema.Token, result2 error) {
+	fake.exchangeTokenMutex.Lock()
+	defer fake.exchangeTokenMutex.Unlock()
+	fake.ExchangeTokenStub = nil
+	if fake.exchangeTokenReturnsOnCall == nil {
+		fake.exchangeTokenReturnsOnCall = make(map[int]struct {
+			result1 *schema.Token
+			result2 error
+		})
+	}
+	fake.exchangeTokenReturnsOnCall[i] = struct {
+		result1 *schema.Token
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) FetchIssuer() (string, error) {
 	fake.fetchIssuerMutex.Lock()
 	ret, specificReturn := fake.fetchIssuerReturnsOnCall[len(fake.fetchIssuerArgsForCall)]
@@ -249,18 +735,17 @@ func (fake *FakeClient) FetchKeyReturnsOnCall(i int, result1 string, result2 err
 	}{result1, result2}
 }
 
-func (fake *FakeClient) FetchToken(arg1 bool) (*schema.Token, error) {
-	fake.fetchTokenMutex.Lock()
-	ret, specificReturn := fake.fetchTokenReturnsOnCall[len(fake.fetchTokenArgsForCall)]
-	fake.fetchTokenArgsForCall = append(fake.fetchTokenArgsForCall, struct {
-		arg1 bool
-	}{arg1})
-	stub := fake.FetchTokenStub
-	fakeReturns := fake.fetchTokenReturns
-	fake.recordInvocation("FetchToken", []interface{}{arg1})
-	fake.fetchTokenMutex.Unlock()
+func (fake *FakeClient) FetchKeySet() (*schema.JSONWebKeySet, error) {
+	fake.fetchKeySetMutex.Lock()
+	ret, specificReturn := fake.fetchKeySetReturnsOnCall[len(fake.fetchKeySetArgsForCall)]
+	fake.fetchKeySetArgsForCall = append(fake.fetchKeySetArgsForCall, struct {
+	}{})
+	stub := fake.FetchKeySetStub
+	fakeReturns := fake.fetchKeySetReturns
+	fake.recordInvocation("FetchKeySet", []interface{}{})
+	fake.fetchKeySetMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -268,46 +753,752 @@ func (fake *FakeClient) FetchToken(arg1 bool) (*schema.Token, error) {
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeClient) FetchTokenCallCount() int {
-	fake.fetchTokenMutex.RLock()
-	defer fake.fetchTokenMutex.RUnlock()
-	return len(fake.fetchTokenArgsForCall)
+func (fake *FakeClient) FetchKeySetCallCount() int {
+	fake.fetchKeySetMutex.RLock()
+	defer fake.fetchKeySetMutex.RUnlock()
+	return len(fake.fetchKeySetArgsForCall)
 }
 
-func (fake *FakeClient) FetchTokenCalls(stub func(bool) (*schema.Token, error)) {
-	fake.fetchTokenMutex.Lock()
-	defer fake.fetchTokenMutex.Unlock()
-	fake.FetchTokenStub = stub
+func (fake *FakeClient) FetchKeySetCalls(stub func() (*schema.JSONWebKeySet, error)) {
+	fake.fetchKeySetMutex.Lock()
+	defer fake.fetchKeySetMutex.Unlock()
+	fake.FetchKeySetStub = stub
 }
 
-func (fake *FakeClient) FetchTokenArgsForCall(i int) bool {
-	fake.fetchTokenMutex.RLock()
-	defer fake.fetchTokenMutex.RUnlock()
-	argsForCall := fake.fetchTokenArgsForCall[i]
-	return argsForCall.arg1
+func (fake *FakeClient) FetchKeySetReturns(result1 *schema.JSONWebKeySet, result2 error) {
+	fake.fetchKeySetMutex.Lock()
+	defer fake.fetchKeySetMutex.Unlock()
+	fake.FetchKeySetStub = nil
+	fake.fetchKeySetReturns = struct {
+		result1 *schema.JSONWebKeySet
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FetchKeySetReturnsOnCall(i int, result1 *schema.JSONWebKeySet, result2 error) {
+	fake.fetchKeySetMutex.Lock()
+	defer fake.fetchKeySetMutex.Unlock()
+	fake.FetchKeySetStub = nil
+	if fake.fetchKeySetReturnsOnCall == nil {
+		fake.fetchKeySetReturnsOnCall = make(map[int]struct {
+			result1 *schema.JSONWebKeySet
+			result2 error
+		})
+	}
+	fake.fetchKeySetReturnsOnCall[i] = struct {
+		result1 *schema.JSONWebKeySet
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FetchOpenIDConfiguration(arg1 bool) (*schema.OpenIDConfiguration, error) {
+	fake.fetchOpenIDConfigurationMutex.Lock()
+	ret, specificReturn := fake.fetchOpenIDConfigurationReturnsOnCall[len(fake.fetchOpenIDConfigurationArgsForCall)]
+	fake.fetchOpenIDConfigurationArgsForCall = append(fake.fetchOpenIDConfigurationArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.FetchOpenIDConfigurationStub
+	fakeReturns := fake.fetchOpenIDConfigurationReturns
+	fake.recordInvocation("FetchOpenIDConfiguration", []interface{}{arg1})
+	fake.fetchOpenIDConfigurationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) FetchOpenIDConfigurationCallCount() int {
+	fake.fetchOpenIDConfigurationMutex.RLock()
+	defer fake.fetchOpenIDConfigurationMutex.RUnlock()
+	return len(fake.fetchOpenIDConfigurationArgsForCall)
+}
+
+func (fake *FakeClient) FetchOpenIDConfigurationCalls(stub func(bool) (*schema.OpenIDConfiguration, error)) {
+	fake.fetchOpenIDConfigurationMutex.Lock()
+	defer fake.fetchOpenIDConfigurationMutex.Unlock()
+	fake.FetchOpenIDConfigurationStub = stub
+}
+
+func (fake *FakeClient) FetchOpenIDConfigurationArgsForCall(i int) bool {
+	fake.fetchOpenIDConfigurationMutex.RLock()
+	defer fake.fetchOpenIDConfigurationMutex.RUnlock()
+	argsForCall := fake.fetchOpenIDConfigurationArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) FetchOpenIDConfigurationReturns(result1 *schema.OpenIDConfiguration, result2 error) {
+	fake.fetchOpenIDConfigurationMutex.Lock()
+	defer fake.fetchOpenIDConfigurationMutex.Unlock()
+	fake.FetchOpenIDConfigurationStub = nil
+	fake.fetchOpenIDConfigurationReturns = struct {
+		result1 *schema.OpenIDConfiguration
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FetchOpenIDConfigurationReturnsOnCall(i int, result1 *schema.OpenIDConfiguration, result2 error) {
+	fake.fetchOpenIDConfigurationMutex.Lock()
+	defer fake.fetchOpenIDConfigurationMutex.Unlock()
+	fake.FetchOpenIDConfigurationStub = nil
+	if fake.fetchOpenIDConfigurationReturnsOnCall == nil {
+		fake.fetchOpenIDConfigurationReturnsOnCall = make(map[int]struct {
+			result1 *schema.OpenIDConfiguration
+			result2 error
+		})
+	}
+	fake.fetchOpenIDConfigurationReturnsOnCall[i] = struct {
+		result1 *schema.OpenIDConfiguration
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FetchPasscodeToken(arg1 string, arg2 ...uaa_go_client.TokenOption) (*schema.Token, error) {
+	fake.fetchPasscodeTokenMutex.Lock()
+	ret, specificReturn := fake.fetchPasscodeTokenReturnsOnCall[len(fake.fetchPasscodeTokenArgsForCall)]
+	fake.fetchPasscodeTokenArgsForCall = append(fake.fetchPasscodeTokenArgsForCall, struct {
+		arg1 string
+		arg2 []uaa_go_client.TokenOption
+	}{arg1, arg2})
+	stub := fake.FetchPasscodeTokenStub
+	fakeReturns := fake.fetchPasscodeTokenReturns
+	fake.recordInvocation("FetchPasscodeToken", []interface{}{arg1, arg2})
+	fake.fetchPasscodeTokenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) FetchPasscodeTokenCallCount() int {
+	fake.fetchPasscodeTokenMutex.RLock()
+	defer fake.fetchPasscodeTokenMutex.RUnlock()
+	return len(fake.fetchPasscodeTokenArgsForCall)
+}
+
+func (fake *FakeClient) FetchPasscodeTokenCalls(stub func(string, ...uaa_go_client.TokenOption) (*schema.Token, error)) {
+	fake.fetchPasscodeTokenMutex.Lock()
+	defer fake.fetchPasscodeTokenMutex.Unlock()
+	fake.FetchPasscodeTokenStub = stub
+}
+
+func (fake *FakeClient) FetchPasscodeTokenArgsForCall(i int) (string, []uaa_go_client.TokenOption) {
+	fake.fetchPasscodeTokenMutex.RLock()
+	defer fake.fetchPasscodeTokenMutex.RUnlock()
+	argsForCall := fake.fetchPasscodeTokenArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) FetchPasscodeTokenReturns(result1 *schema.Token, result2 error) {
+	fake.fetchPasscodeTokenMutex.Lock()
+	defer fake.fetchPasscodeTokenMutex.Unlock()
+	fake.FetchPasscodeTokenStub = nil
+	fake.fetchPasscodeTokenReturns = struct {
+		result1 *schema.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FetchPasscodeTokenReturnsOnCall(i int, result1 *schema.Token, result2 error) {
+	fake.fetchPasscodeTokenMutex.Lock()
+	defer fake.fetchPasscodeTokenMutex.Unlock()
+	fake.FetchPasscodeTokenStub = nil
+	if fake.fetchPasscodeTokenReturnsOnCall == nil {
+		fake.fetchPasscodeTokenReturnsOnCall = make(map[int]struct {
+			result1 *schema.Token
+			result2 error
+		})
+	}
+	fake.fetchPasscodeTokenReturnsOnCall[i] = struct {
+		result1 *schema.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FetchToken(arg1 bool) (*schema.Token, error) {
+	fake.fetchTokenMutex.Lock()
+	ret, specificReturn := fake.fetchTokenReturnsOnCall[len(fake.fetchTokenArgsForCall)]
+	fake.fetchTokenArgsForCall = append(fake.fetchTokenArgsForCall, struct {
+		arg1 bool
+	}{arg1})
+	stub := fake.FetchTokenStub
+	fakeReturns := fake.fetchTokenReturns
+	fake.recordInvocation("FetchToken", []interface{}{arg1})
+	fake.fetchTokenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) FetchTokenCallCount() int {
+	fake.fetchTokenMutex.RLock()
+	defer fake.fetchTokenMutex.RUnlock()
+	return len(fake.fetchTokenArgsForCall)
+}
+
+func (fake *FakeClient) FetchTokenCalls(stub func(bool) (*schema.Token, error)) {
+	fake.fetchTokenMutex.Lock()
+	defer fake.fetchTokenMutex.Unlock()
+	fake.FetchTokenStub = stub
+}
+
+func (fake *FakeClient) FetchTokenArgsForCall(i int) bool {
+	fake.fetchTokenMutex.RLock()
+	defer fake.fetchTokenMutex.RUnlock()
+	argsForCall := fake.fetchTokenArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) FetchTokenReturns(result1 *schema.Token, result2 error) {
+	fake.fetchTokenMutex.Lock()
+	defer fake.fetchTokenMutex.Unlock()
+	fake.FetchTokenStub = nil
+	fake.fetchTokenReturns = struct {
+		result1 *schema.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FetchTokenReturnsOnCall(i int, result1 *schema.Token, result2 error) {
+	fake.fetchTokenMutex.Lock()
+	defer fake.fetchTokenMutex.Unlock()
+	fake.FetchTokenStub = nil
+	if fake.fetchTokenReturnsOnCall == nil {
+		fake.fetchTokenReturnsOnCall = make(map[int]struct {
+			result1 *schema.Token
+			result2 error
+		})
+	}
+	fake.fetchTokenReturnsOnCall[i] = struct {
+		result1 *schema.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FetchTokenForScopes(arg1 []string, arg2 bool) (*schema.Token, error) {
+	var arg1Copy []string
+	if arg1 != nil {
+		arg1Copy = make([]string, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.fetchTokenForScopesMutex.Lock()
+	ret, specificReturn := fake.fetchTokenForScopesReturnsOnCall[len(fake.fetchTokenForScopesArgsForCall)]
+	fake.fetchTokenForScopesArgsForCall = append(fake.fetchTokenForScopesArgsForCall, struct {
+		arg1 []string
+		arg2 bool
+	}{arg1Copy, arg2})
+	stub := fake.FetchTokenForScopesStub
+	fakeReturns := fake.fetchTokenForScopesReturns
+	fake.recordInvocation("FetchTokenForScopes", []interface{}{arg1Copy, arg2})
+	fake.fetchTokenForScopesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) FetchTokenForScopesCallCount() int {
+	fake.fetchTokenForScopesMutex.RLock()
+	defer fake.fetchTokenForScopesMutex.RUnlock()
+	return len(fake.fetchTokenForScopesArgsForCall)
+}
+
+func (fake *FakeClient) FetchTokenForScopesCalls(stub func([]string, bool) (*schema.Token, error)) {
+	fake.fetchTokenForScopesMutex.Lock()
+	defer fake.fetchTokenForScopesMutex.Unlock()
+	fake.FetchTokenForScopesStub = stub
+}
+
+func (fake *FakeClient) FetchTokenForScopesArgsForCall(i int) ([]string, bool) {
+	fake.fetchTokenForScopesMutex.RLock()
+	defer fake.fetchTokenForScopesMutex.RUnlock()
+	argsForCall := fake.fetchTokenForScopesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) FetchTokenForScopesReturns(result1 *schema.Token, result2 error) {
+	fake.fetchTokenForScopesMutex.Lock()
+	defer fake.fetchTokenForScopesMutex.Unlock()
+	fake.FetchTokenForScopesStub = nil
+	fake.fetchTokenForScopesReturns = struct {
+		result1 *schema.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FetchTokenForScopesReturnsOnCall(i int, result1 *schema.Token, result2 error) {
+	fake.fetchTokenForScopesMutex.Lock()
+	defer fake.fetchTokenForScopesMutex.Unlock()
+	fake.FetchTokenForScopesStub = nil
+	if fake.fetchTokenForScopesReturnsOnCall == nil {
+		fake.fetchTokenForScopesReturnsOnCall = make(map[int]struct {
+			result1 *schema.Token
+			result2 error
+		})
+	}
+	fake.fetchTokenForScopesReturnsOnCall[i] = struct {
+		result1 *schema.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FetchTokenWithAssertion(arg1 string, arg2 ...string) (*schema.Token, error) {
+	fake.fetchTokenWithAssertionMutex.Lock()
+	ret, specificReturn := fake.fetchTokenWithAssertionReturnsOnCall[len(fake.fetchTokenWithAssertionArgsForCall)]
+	fake.fetchTokenWithAssertionArgsForCall = append(fake.fetchTokenWithAssertionArgsForCall, struct {
+		arg1 string
+		arg2 []string
+	}{arg1, arg2})
+	stub := fake.FetchTokenWithAssertionStub
+	fakeReturns := fake.fetchTokenWithAssertionReturns
+	fake.recordInvocation("FetchTokenWithAssertion", []interface{}{arg1, arg2})
+	fake.fetchTokenWithAssertionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) FetchTokenWithAssertionCallCount() int {
+	fake.fetchTokenWithAssertionMutex.RLock()
+	defer fake.fetchTokenWithAssertionMutex.RUnlock()
+	return len(fake.fetchTokenWithAssertionArgsForCall)
+}
+
+func (fake *FakeClient) FetchTokenWithAssertionCalls(stub func(string, ...string) (*schema.Token, error)) {
+	fake.fetchTokenWithAssertionMutex.Lock()
+	defer fake.fetchTokenWithAssertionMutex.Unlock()
+	fake.FetchTokenWithAssertionStub = stub
+}
+
+func (fake *FakeClient) FetchTokenWithAssertionArgsForCall(i int) (string, []string) {
+	fake.fetchTokenWithAssertionMutex.RLock()
+	defer fake.fetchTokenWithAssertionMutex.RUnlock()
+	argsForCall := fake.fetchTokenWithAssertionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) FetchTokenWithAssertionReturns(result1 *schema.Token, result2 error) {
+	fake.fetchTokenWithAssertionMutex.Lock()
+	defer fake.fetchTokenWithAssertionMutex.Unlock()
+	fake.FetchTokenWithAssertionStub = nil
+	fake.fetchTokenWithAssertionReturns = struct {
+		result1 *schema.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FetchTokenWithAssertionReturnsOnCall(i int, result1 *schema.Token, result2 error) {
+	fake.fetchTokenWithAssertionMutex.Lock()
+	defer fake.fetchTokenWithAssertionMutex.Unlock()
+	fake.FetchTokenWithAssertionStub = nil
+	if fake.fetchTokenWithAssertionReturnsOnCall == nil {
+		fake.fetchTokenWithAssertionReturnsOnCall = make(map[int]struct {
+			result1 *schema.Token
+			result2 error
+		})
+	}
+	fake.fetchTokenWithAssertionReturnsOnCall[i] = struct {
+		result1 *schema.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FetchUserInfo(arg1 string) (*schema.UserInfo, error) {
+	fake.fetchUserInfoMutex.Lock()
+	ret, specificReturn := fake.fetchUserInfoReturnsOnCall[len(fake.fetchUserInfoArgsForCall)]
+	fake.fetchUserInfoArgsForCall = append(fake.fetchUserInfoArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.FetchUserInfoStub
+	fakeReturns := fake.fetchUserInfoReturns
+	fake.recordInvocation("FetchUserInfo", []interface{}{arg1})
+	fake.fetchUserInfoMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) FetchUserInfoCallCount() int {
+	fake.fetchUserInfoMutex.RLock()
+	defer fake.fetchUserInfoMutex.RUnlock()
+	return len(fake.fetchUserInfoArgsForCall)
+}
+
+func (fake *FakeClient) FetchUserInfoCalls(stub func(string) (*schema.UserInfo, error)) {
+	fake.fetchUserInfoMutex.Lock()
+	defer fake.fetchUserInfoMutex.Unlock()
+	fake.FetchUserInfoStub = stub
+}
+
+func (fake *FakeClient) FetchUserInfoArgsForCall(i int) string {
+	fake.fetchUserInfoMutex.RLock()
+	defer fake.fetchUserInfoMutex.RUnlock()
+	argsForCall := fake.fetchUserInfoArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) FetchUserInfoReturns(result1 *schema.UserInfo, result2 error) {
+	fake.fetchUserInfoMutex.Lock()
+	defer fake.fetchUserInfoMutex.Unlock()
+	fake.FetchUserInfoStub = nil
+	fake.fetchUserInfoReturns = struct {
+		result1 *schema.UserInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FetchUserInfoReturnsOnCall(i int, result1 *schema.UserInfo, result2 error) {
+	fake.fetchUserInfoMutex.Lock()
+	defer fake.fetchUserInfoMutex.Unlock()
+	fake.FetchUserInfoStub = nil
+	if fake.fetchUserInfoReturnsOnCall == nil {
+		fake.fetchUserInfoReturnsOnCall = make(map[int]struct {
+			result1 *schema.UserInfo
+			result2 error
+		})
+	}
+	fake.fetchUserInfoReturnsOnCall[i] = struct {
+		result1 *schema.UserInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FetchUserToken(arg1 string, arg2 string, arg3 ...uaa_go_client.TokenOption) (*schema.Token, error) {
+	fake.fetchUserTokenMutex.Lock()
+	ret, specificReturn := fake.fetchUserTokenReturnsOnCall[len(fake.fetchUserTokenArgsForCall)]
+	fake.fetchUserTokenArgsForCall = append(fake.fetchUserTokenArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 []uaa_go_client.TokenOption
+	}{arg1, arg2, arg3})
+	stub := fake.FetchUserTokenStub
+	fakeReturns := fake.fetchUserTokenReturns
+	fake.recordInvocation("FetchUserToken", []interface{}{arg1, arg2, arg3})
+	fake.fetchUserTokenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) FetchUserTokenCallCount() int {
+	fake.fetchUserTokenMutex.RLock()
+	defer fake.fetchUserTokenMutex.RUnlock()
+	return len(fake.fetchUserTokenArgsForCall)
+}
+
+func (fake *FakeClient) FetchUserTokenCalls(stub func(string, string, ...uaa_go_client.TokenOption) (*schema.Token, error)) {
+	fake.fetchUserTokenMutex.Lock()
+	defer fake.fetchUserTokenMutex.Unlock()
+	fake.FetchUserTokenStub = stub
+}
+
+func (fake *FakeClient) FetchUserTokenArgsForCall(i int) (string, string, []uaa_go_client.TokenOption) {
+	fake.fetchUserTokenMutex.RLock()
+	defer fake.fetchUserTokenMutex.RUnlock()
+	argsForCall := fake.fetchUserTokenArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeClient) FetchUserTokenReturns(result1 *schema.Token, result2 error) {
+	fake.fetchUserTokenMutex.Lock()
+	defer fake.fetchUserTokenMutex.Unlock()
+	fake.FetchUserTokenStub = nil
+	fake.fetchUserTokenReturns = struct {
+		result1 *schema.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FetchUserTokenReturnsOnCall(i int, result1 *schema.Token, result2 error) {
+	fake.fetchUserTokenMutex.Lock()
+	defer fake.fetchUserTokenMutex.Unlock()
+	fake.FetchUserTokenStub = nil
+	if fake.fetchUserTokenReturnsOnCall == nil {
+		fake.fetchUserTokenReturnsOnCall = make(map[int]struct {
+			result1 *schema.Token
+			result2 error
+		})
+	}
+	fake.fetchUserTokenReturnsOnCall[i] = struct {
+		result1 *schema.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FetchUserTokenForClient(arg1 string, arg2 string, arg3 ...string) (*schema.Token, error) {
+	fake.fetchUserTokenForClientMutex.Lock()
+	ret, specificReturn := fake.fetchUserTokenForClientReturnsOnCall[len(fake.fetchUserTokenForClientArgsForCall)]
+	fake.fetchUserTokenForClientArgsForCall = append(fake.fetchUserTokenForClientArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 []string
+	}{arg1, arg2, arg3})
+	stub := fake.FetchUserTokenForClientStub
+	fakeReturns := fake.fetchUserTokenForClientReturns
+	fake.recordInvocation("FetchUserTokenForClient", []interface{}{arg1, arg2, arg3})
+	fake.fetchUserTokenForClientMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) FetchUserTokenForClientCallCount() int {
+	fake.fetchUserTokenForClientMutex.RLock()
+	defer fake.fetchUserTokenForClientMutex.RUnlock()
+	return len(fake.fetchUserTokenForClientArgsForCall)
+}
+
+func (fake *FakeClient) FetchUserTokenForClientCalls(stub func(string, string, ...string) (*schema.Token, error)) {
+	fake.fetchUserTokenForClientMutex.Lock()
+	defer fake.fetchUserTokenForClientMutex.Unlock()
+	fake.FetchUserTokenForClientStub = stub
+}
+
+func (fake *FakeClient) FetchUserTokenForClientArgsForCall(i int) (string, string, []string) {
+	fake.fetchUserTokenForClientMutex.RLock()
+	defer fake.fetchUserTokenForClientMutex.RUnlock()
+	argsForCall := fake.fetchUserTokenForClientArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeClient) FetchUserTokenForClientReturns(result1 *schema.Token, result2 error) {
+	fake.fetchUserTokenForClientMutex.Lock()
+	defer fake.fetchUserTokenForClientMutex.Unlock()
+	fake.FetchUserTokenForClientStub = nil
+	fake.fetchUserTokenForClientReturns = struct {
+		result1 *schema.Token
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) FetchUserTokenForClientReturnsOnCall(i int, result1 *schema.Token, result2 error) {
+	fake.fetchUserTokenForClientMutex.Lock()
+	defer fake.fetchUserTokenForClientMutex.Unlock()
+	fake.FetchUserTokenForClientStub = nil
+	if fake.fetchUserTokenForClientReturnsOnCall == nil {
+		fake.fetchUserTokenForClientReturnsOnCall = make(map[int]struct {
+			result1 *schema.Token
+			result2 error
+		})
+	}
+	fake.fetchUserTokenForClientReturnsOnCall[i] = struct {
+		result1 *schema.Token
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeClient) FetchTokenReturns(result1 *schema.Token, result2 error) {
-	fake.fetchTokenMutex.Lock()
-	defer fake.fetchTokenMutex.Unlock()
-	fake.FetchTokenStub = nil
-	fake.fetchTokenReturns = struct {
+func (fake *FakeClient) ForZone(arg1 schema.IdentityZone) uaa_go_client.Client {
+	fake.forZoneMutex.Lock()
+	ret, specificReturn := fake.forZoneReturnsOnCall[len(fake.forZoneArgsForCall)]
+	fake.forZoneArgsForCall = append(fake.forZoneArgsForCall, struct {
+		arg1 schema.IdentityZone
+	}{arg1})
+	stub := fake.ForZoneStub
+	fakeReturns := fake.forZoneReturns
+	fake.recordInvocation("ForZone", []interface{}{arg1})
+	fake.forZoneMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) ForZoneCallCount() int {
+	fake.forZoneMutex.RLock()
+	defer fake.forZoneMutex.RUnlock()
+	return len(fake.forZoneArgsForCall)
+}
+
+func (fake *FakeClient) ForZoneCalls(stub func(schema.IdentityZone) uaa_go_client.Client) {
+	fake.forZoneMutex.Lock()
+	defer fake.forZoneMutex.Unlock()
+	fake.ForZoneStub = stub
+}
+
+func (fake *FakeClient) ForZoneArgsForCall(i int) schema.IdentityZone {
+	fake.forZoneMutex.RLock()
+	defer fake.forZoneMutex.RUnlock()
+	argsForCall := fake.forZoneArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) ForZoneReturns(result1 uaa_go_client.Client) {
+	fake.forZoneMutex.Lock()
+	defer fake.forZoneMutex.Unlock()
+	fake.ForZoneStub = nil
+	fake.forZoneReturns = struct {
+		result1 uaa_go_client.Client
+	}{result1}
+}
+
+func (fake *FakeClient) ForZoneReturnsOnCall(i int, result1 uaa_go_client.Client) {
+	fake.forZoneMutex.Lock()
+	defer fake.forZoneMutex.Unlock()
+	fake.ForZoneStub = nil
+	if fake.forZoneReturnsOnCall == nil {
+		fake.forZoneReturnsOnCall = make(map[int]struct {
+			result1 uaa_go_client.Client
+		})
+	}
+	fake.forZoneReturnsOnCall[i] = struct {
+		result1 uaa_go_client.Client
+	}{result1}
+}
+
+func (fake *FakeClient) IntrospectToken(arg1 string) (*schema.TokenIntrospection, error) {
+	fake.introspectTokenMutex.Lock()
+	ret, specificReturn := fake.introspectTokenReturnsOnCall[len(fake.introspectTokenArgsForCall)]
+	fake.introspectTokenArgsForCall = append(fake.introspectTokenArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.IntrospectTokenStub
+	fakeReturns := fake.introspectTokenReturns
+	fake.recordInvocation("IntrospectToken", []interface{}{arg1})
+	fake.introspectTokenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) IntrospectTokenCallCount() int {
+	fake.introspectTokenMutex.RLock()
+	defer fake.introspectTokenMutex.RUnlock()
+	return len(fake.introspectTokenArgsForCall)
+}
+
+func (fake *FakeClient) IntrospectTokenCalls(stub func(string) (*schema.TokenIntrospection, error)) {
+	fake.introspectTokenMutex.Lock()
+	defer fake.introspectTokenMutex.Unlock()
+	fake.IntrospectTokenStub = stub
+}
+
+func (fake *FakeClient) IntrospectTokenArgsForCall(i int) string {
+	fake.introspectTokenMutex.RLock()
+	defer fake.introspectTokenMutex.RUnlock()
+	argsForCall := fake.introspectTokenArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) IntrospectTokenReturns(result1 *schema.TokenIntrospection, result2 error) {
+	fake.introspectTokenMutex.Lock()
+	defer fake.introspectTokenMutex.Unlock()
+	fake.IntrospectTokenStub = nil
+	fake.introspectTokenReturns = struct {
+		result1 *schema.TokenIntrospection
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) IntrospectTokenReturnsOnCall(i int, result1 *schema.TokenIntrospection, result2 error) {
+	fake.introspectTokenMutex.Lock()
+	defer fake.introspectTokenMutex.Unlock()
+	fake.IntrospectTokenStub = nil
+	if fake.introspectTokenReturnsOnCall == nil {
+		fake.introspectTokenReturnsOnCall = make(map[int]struct {
+			result1 *schema.TokenIntrospection
+			result2 error
+		})
+	}
+	fake.introspectTokenReturnsOnCall[i] = struct {
+		result1 *schema.TokenIntrospection
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) RefreshToken(arg1 string) (*schema.Token, error) {
+	fake.refreshTokenMutex.Lock()
+	ret, specificReturn := fake.refreshTokenReturnsOnCall[len(fake.refreshTokenArgsForCall)]
+	fake.refreshTokenArgsForCall = append(fake.refreshTokenArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.RefreshTokenStub
+	fakeReturns := fake.refreshTokenReturns
+	fake.recordInvocation("RefreshToken", []interface{}{arg1})
+	fake.refreshTokenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) RefreshTokenCallCount() int {
+	fake.refreshTokenMutex.RLock()
+	defer fake.refreshTokenMutex.RUnlock()
+	return len(fake.refreshTokenArgsForCall)
+}
+
+func (fake *FakeClient) RefreshTokenCalls(stub func(string) (*schema.Token, error)) {
+	fake.refreshTokenMutex.Lock()
+	defer fake.refreshTokenMutex.Unlock()
+	fake.RefreshTokenStub = stub
+}
+
+func (fake *FakeClient) RefreshTokenArgsForCall(i int) string {
+	fake.refreshTokenMutex.RLock()
+	defer fake.refreshTokenMutex.RUnlock()
+	argsForCall := fake.refreshTokenArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) RefreshTokenReturns(result1 *schema.Token, result2 error) {
+	fake.refreshTokenMutex.Lock()
+	defer fake.refreshTokenMutex.Unlock()
+	fake.RefreshTokenStub = nil
+	fake.refreshTokenReturns = struct {
 		result1 *schema.Token
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeClient) FetchTokenReturnsOnCall(i int, result1 *schema.Token, result2 error) {
-	fake.fetchTokenMutex.Lock()
-	defer fake.fetchTokenMutex.Unlock()
-	fake.FetchTokenStub = nil
-	if fake.fetchTokenReturnsOnCall == nil {
-		fake.fetchTokenReturnsOnCall = make(map[int]struct {
+func (fake *FakeClient) RefreshTokenReturnsOnCall(i int, result1 *schema.Token, result2 error) {
+	fake.refreshTokenMutex.Lock()
+	defer fake.refreshTokenMutex.Unlock()
+	fake.RefreshTokenStub = nil
+	if fake.refreshTokenReturnsOnCall == nil {
+		fake.refreshTokenReturnsOnCall = make(map[int]struct {
 			result1 *schema.Token
 			result2 error
 		})
 	}
-	fake.fetchTokenReturnsOnCall[i] = struct {
+	fake.refreshTokenReturnsOnCall[i] = struct {
 		result1 *schema.Token
 		result2 error
 	}{result1, result2}
@@ -377,19 +1568,437 @@ func (fake *FakeClient) RegisterOauthClientReturnsOnCall(i int, result1 *schema.
 	}{result1, result2}
 }
 
+func (fake *FakeClient) RevokeClientTokens(arg1 string) error {
+	fake.revokeClientTokensMutex.Lock()
+	ret, specificReturn := fake.revokeClientTokensReturnsOnCall[len(fake.revokeClientTokensArgsForCall)]
+	fake.revokeClientTokensArgsForCall = append(fake.revokeClientTokensArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.RevokeClientTokensStub
+	fakeReturns := fake.revokeClientTokensReturns
+	fake.recordInvocation("RevokeClientTokens", []interface{}{arg1})
+	fake.revokeClientTokensMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) RevokeClientTokensCallCount() int {
+	fake.revokeClientTokensMutex.RLock()
+	defer fake.revokeClientTokensMutex.RUnlock()
+	return len(fake.revokeClientTokensArgsForCall)
+}
+
+func (fake *FakeClient) RevokeClientTokensCalls(stub func(string) error) {
+	fake.revokeClientTokensMutex.Lock()
+	defer fake.revokeClientTokensMutex.Unlock()
+	fake.RevokeClientTokensStub = stub
+}
+
+func (fake *FakeClient) RevokeClientTokensArgsForCall(i int) string {
+	fake.revokeClientTokensMutex.RLock()
+	defer fake.revokeClientTokensMutex.RUnlock()
+	argsForCall := fake.revokeClientTokensArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) RevokeClientTokensReturns(result1 error) {
+	fake.revokeClientTokensMutex.Lock()
+	defer fake.revokeClientTokensMutex.Unlock()
+	fake.RevokeClientTokensStub = nil
+	fake.revokeClientTokensReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) RevokeClientTokensReturnsOnCall(i int, result1 error) {
+	fake.revokeClientTokensMutex.Lock()
+	defer fake.revokeClientTokensMutex.Unlock()
+	fake.RevokeClientTokensStub = nil
+	if fake.revokeClientTokensReturnsOnCall == nil {
+		fake.revokeClientTokensReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.revokeClientTokensReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) RevokeToken(arg1 string) error {
+	fake.revokeTokenMutex.Lock()
+	ret, specificReturn := fake.revokeTokenReturnsOnCall[len(fake.revokeTokenArgsForCall)]
+	fake.revokeTokenArgsForCall = append(fake.revokeTokenArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.RevokeTokenStub
+	fakeReturns := fake.revokeTokenReturns
+	fake.recordInvocation("RevokeToken", []interface{}{arg1})
+	fake.revokeTokenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) RevokeTokenCallCount() int {
+	fake.revokeTokenMutex.RLock()
+	defer fake.revokeTokenMutex.RUnlock()
+	return len(fake.revokeTokenArgsForCall)
+}
+
+func (fake *FakeClient) RevokeTokenCalls(stub func(string) error) {
+	fake.revokeTokenMutex.Lock()
+	defer fake.revokeTokenMutex.Unlock()
+	fake.RevokeTokenStub = stub
+}
+
+func (fake *FakeClient) RevokeTokenArgsForCall(i int) string {
+	fake.revokeTokenMutex.RLock()
+	defer fake.revokeTokenMutex.RUnlock()
+	argsForCall := fake.revokeTokenArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) RevokeTokenReturns(result1 error) {
+	fake.revokeTokenMutex.Lock()
+	defer fake.revokeTokenMutex.Unlock()
+	fake.RevokeTokenStub = nil
+	fake.revokeTokenReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) RevokeTokenReturnsOnCall(i int, result1 error) {
+	fake.revokeTokenMutex.Lock()
+	defer fake.revokeTokenMutex.Unlock()
+	fake.RevokeTokenStub = nil
+	if fake.revokeTokenReturnsOnCall == nil {
+		fake.revokeTokenReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.revokeTokenReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) RevokeUserClientTokens(arg1 string, arg2 string) error {
+	fake.revokeUserClientTokensMutex.Lock()
+	ret, specificReturn := fake.revokeUserClientTokensReturnsOnCall[len(fake.revokeUserClientTokensArgsForCall)]
+	fake.revokeUserClientTokensArgsForCall = append(fake.revokeUserClientTokensArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.RevokeUserClientTokensStub
+	fakeReturns := fake.revokeUserClientTokensReturns
+	fake.recordInvocation("RevokeUserClientTokens", []interface{}{arg1, arg2})
+	fake.revokeUserClientTokensMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) RevokeUserClientTokensCallCount() int {
+	fake.revokeUserClientTokensMutex.RLock()
+	defer fake.revokeUserClientTokensMutex.RUnlock()
+	return len(fake.revokeUserClientTokensArgsForCall)
+}
+
+func (fake *FakeClient) RevokeUserClientTokensCalls(stub func(string, string) error) {
+	fake.revokeUserClientTokensMutex.Lock()
+	defer fake.revokeUserClientTokensMutex.Unlock()
+	fake.RevokeUserClientTokensStub = stub
+}
+
+func (fake *FakeClient) RevokeUserClientTokensArgsForCall(i int) (string, string) {
+	fake.revokeUserClientTokensMutex.RLock()
+	defer fake.revokeUserClientTokensMutex.RUnlock()
+	argsForCall := fake.revokeUserClientTokensArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) RevokeUserClientTokensReturns(result1 error) {
+	fake.revokeUserClientTokensMutex.Lock()
+	defer fake.revokeUserClientTokensMutex.Unlock()
+	fake.RevokeUserClientTokensStub = nil
+	fake.revokeUserClientTokensReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) RevokeUserClientTokensReturnsOnCall(i int, result1 error) {
+	fake.revokeUserClientTokensMutex.Lock()
+	defer fake.revokeUserClientTokensMutex.Unlock()
+	fake.RevokeUserClientTokensStub = nil
+	if fake.revokeUserClientTokensReturnsOnCall == nil {
+		fake.revokeUserClientTokensReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.revokeUserClientTokensReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) RevokeUserTokens(arg1 string) error {
+	fake.revokeUserTokensMutex.Lock()
+	ret, specificReturn := fake.revokeUserTokensReturnsOnCall[len(fake.revokeUserTokensArgsForCall)]
+	fake.revokeUserTokensArgsForCall = append(fake.revokeUserTokensArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.RevokeUserTokensStub
+	fakeReturns := fake.revokeUserTokensReturns
+	fake.recordInvocation("RevokeUserTokens", []interface{}{arg1})
+	fake.revokeUserTokensMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeClient) RevokeUserTokensCallCount() int {
+	fake.revokeUserTokensMutex.RLock()
+	defer fake.revokeUserTokensMutex.RUnlock()
+	return len(fake.revokeUserTokensArgsForCall)
+}
+
+func (fake *FakeClient) RevokeUserTokensCalls(stub func(string) error) {
+	fake.revokeUserTokensMutex.Lock()
+	defer fake.revokeUserTokensMutex.Unlock()
+	fake.RevokeUserTokensStub = stub
+}
+
+func (fake *FakeClient) RevokeUserTokensArgsForCall(i int) string {
+	fake.revokeUserTokensMutex.RLock()
+	defer fake.revokeUserTokensMutex.RUnlock()
+	argsForCall := fake.revokeUserTokensArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeClient) RevokeUserTokensReturns(result1 error) {
+	fake.revokeUserTokensMutex.Lock()
+	defer fake.revokeUserTokensMutex.Unlock()
+	fake.RevokeUserTokensStub = nil
+	fake.revokeUserTokensReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) RevokeUserTokensReturnsOnCall(i int, result1 error) {
+	fake.revokeUserTokensMutex.Lock()
+	defer fake.revokeUserTokensMutex.Unlock()
+	fake.RevokeUserTokensStub = nil
+	if fake.revokeUserTokensReturnsOnCall == nil {
+		fake.revokeUserTokensReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.revokeUserTokensReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeClient) VerifyIDToken(arg1 string, arg2 string, arg3 string) (*schema.IDTokenClaims, error) {
+	fake.verifyIDTokenMutex.Lock()
+	ret, specificReturn := fake.verifyIDTokenReturnsOnCall[len(fake.verifyIDTokenArgsForCall)]
+	fake.verifyIDTokenArgsForCall = append(fake.verifyIDTokenArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 string
+	}{arg1, arg2, arg3})
+	stub := fake.VerifyIDTokenStub
+	fakeReturns := fake.verifyIDTokenReturns
+	fake.recordInvocation("VerifyIDToken", []interface{}{arg1, arg2, arg3})
+	fake.verifyIDTokenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) VerifyIDTokenCallCount() int {
+	fake.verifyIDTokenMutex.RLock()
+	defer fake.verifyIDTokenMutex.RUnlock()
+	return len(fake.verifyIDTokenArgsForCall)
+}
+
+func (fake *FakeClient) VerifyIDTokenCalls(stub func(string, string, string) (*schema.IDTokenClaims, error)) {
+	fake.verifyIDTokenMutex.Lock()
+	defer fake.verifyIDTokenMutex.Unlock()
+	fake.VerifyIDTokenStub = stub
+}
+
+func (fake *FakeClient) VerifyIDTokenArgsForCall(i int) (string, string, string) {
+	fake.verifyIDTokenMutex.RLock()
+	defer fake.verifyIDTokenMutex.RUnlock()
+	argsForCall := fake.verifyIDTokenArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeClient) VerifyIDTokenReturns(result1 *schema.IDTokenClaims, result2 error) {
+	fake.verifyIDTokenMutex.Lock()
+	defer fake.verifyIDTokenMutex.Unlock()
+	fake.VerifyIDTokenStub = nil
+	fake.verifyIDTokenReturns = struct {
+		result1 *schema.IDTokenClaims
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) VerifyIDTokenReturnsOnCall(i int, result1 *schema.IDTokenClaims, result2 error) {
+	fake.verifyIDTokenMutex.Lock()
+	defer fake.verifyIDTokenMutex.Unlock()
+	fake.VerifyIDTokenStub = nil
+	if fake.verifyIDTokenReturnsOnCall == nil {
+		fake.verifyIDTokenReturnsOnCall = make(map[int]struct {
+			result1 *schema.IDTokenClaims
+			result2 error
+		})
+	}
+	fake.verifyIDTokenReturnsOnCall[i] = struct {
+		result1 *schema.IDTokenClaims
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) VerifyToken(arg1 string, arg2 ...uaa_go_client.VerifyOption) (*schema.Claims, error) {
+	fake.verifyTokenMutex.Lock()
+	ret, specificReturn := fake.verifyTokenReturnsOnCall[len(fake.verifyTokenArgsForCall)]
+	fake.verifyTokenArgsForCall = append(fake.verifyTokenArgsForCall, struct {
+		arg1 string
+		arg2 []uaa_go_client.VerifyOption
+	}{arg1, arg2})
+	stub := fake.VerifyTokenStub
+	fakeReturns := fake.verifyTokenReturns
+	fake.recordInvocation("VerifyToken", []interface{}{arg1, arg2})
+	fake.verifyTokenMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2...)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeClient) VerifyTokenCallCount() int {
+	fake.verifyTokenMutex.RLock()
+	defer fake.verifyTokenMutex.RUnlock()
+	return len(fake.verifyTokenArgsForCall)
+}
+
+func (fake *FakeClient) VerifyTokenCalls(stub func(string, ...uaa_go_client.VerifyOption) (*schema.Claims, error)) {
+	fake.verifyTokenMutex.Lock()
+	defer fake.verifyTokenMutex.Unlock()
+	fake.VerifyTokenStub = stub
+}
+
+func (fake *FakeClient) VerifyTokenArgsForCall(i int) (string, []uaa_go_client.VerifyOption) {
+	fake.verifyTokenMutex.RLock()
+	defer fake.verifyTokenMutex.RUnlock()
+	argsForCall := fake.verifyTokenArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeClient) VerifyTokenReturns(result1 *schema.Claims, result2 error) {
+	fake.verifyTokenMutex.Lock()
+	defer fake.verifyTokenMutex.Unlock()
+	fake.VerifyTokenStub = nil
+	fake.verifyTokenReturns = struct {
+		result1 *schema.Claims
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeClient) VerifyTokenReturnsOnCall(i int, result1 *schema.Claims, result2 error) {
+	fake.verifyTokenMutex.Lock()
+	defer fake.verifyTokenMutex.Unlock()
+	fake.VerifyTokenStub = nil
+	if fake.verifyTokenReturnsOnCall == nil {
+		fake.verifyTokenReturnsOnCall = make(map[int]struct {
+			result1 *schema.Claims
+			result2 error
+		})
+	}
+	fake.verifyTokenReturnsOnCall[i] = struct {
+		result1 *schema.Claims
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.authorizationCodeURLMutex.RLock()
+	defer fake.authorizationCodeURLMutex.RUnlock()
+	fake.closeMutex.RLock()
+	defer fake.closeMutex.RUnlock()
 	fake.decodeTokenMutex.RLock()
 	defer fake.decodeTokenMutex.RUnlock()
+	fake.exchangeAuthorizationCodeMutex.RLock()
+	defer fake.exchangeAuthorizationCodeMutex.RUnlock()
+	fake.exchangeTokenMutex.RLock()
+	defer fake.exchangeTokenMutex.RUnlock()
 	fake.fetchIssuerMutex.RLock()
 	defer fake.fetchIssuerMutex.RUnlock()
 	fake.fetchKeyMutex.RLock()
 	defer fake.fetchKeyMutex.RUnlock()
+	fake.fetchKeySetMutex.RLock()
+	defer fake.fetchKeySetMutex.RUnlock()
+	fake.fetchOpenIDConfigurationMutex.RLock()
+	defer fake.fetchOpenIDConfigurationMutex.RUnlock()
+	fake.fetchPasscodeTokenMutex.RLock()
+	defer fake.fetchPasscodeTokenMutex.RUnlock()
 	fake.fetchTokenMutex.RLock()
 	defer fake.fetchTokenMutex.RUnlock()
+	fake.fetchTokenForScopesMutex.RLock()
+	defer fake.fetchTokenForScopesMutex.RUnlock()
+	fake.fetchTokenWithAssertionMutex.RLock()
+	defer fake.fetchTokenWithAssertionMutex.RUnlock()
+	fake.fetchUserInfoMutex.RLock()
+	defer fake.fetchUserInfoMutex.RUnlock()
+	fake.fetchUserTokenMutex.RLock()
+	defer fake.fetchUserTokenMutex.RUnlock()
+	fake.fetchUserTokenForClientMutex.RLock()
+	defer fake.fetchUserTokenForClientMutex.RUnlock()
+	fake.forZoneMutex.RLock()
+	defer fake.forZoneMutex.RUnlock()
+	fake.introspectTokenMutex.RLock()
+	defer fake.introspectTokenMutex.RUnlock()
+	fake.refreshTokenMutex.RLock()
+	defer fake.refreshTokenMutex.RUnlock()
 	fake.registerOauthClientMutex.RLock()
 	defer fake.registerOauthClientMutex.RUnlock()
+	fake.revokeClientTokensMutex.RLock()
+	defer fake.revokeClientTokensMutex.RUnlock()
+	fake.revokeTokenMutex.RLock()
+	defer fake.revokeTokenMutex.RUnlock()
+	fake.revokeUserClientTokensMutex.RLock()
+	defer fake.revokeUserClientTokensMutex.RUnlock()
+	fake.revokeUserTokensMutex.RLock()
+	defer fake.revokeUserTokensMutex.RUnlock()
+	fake.verifyIDTokenMutex.RLock()
+	defer fake.verifyIDTokenMutex.RUnlock()
+	fake.verifyTokenMutex.RLock()
+	defer fake.verifyTokenMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
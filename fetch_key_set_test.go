@@ -0,0 +1,157 @@
+package uaa_go_client_test
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+	"github.com/golang-jwt/jwt/v4"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Fetch Key Set", func() {
+	var (
+		client uaa_go_client.Client
+
+		oldPrivateKey, newPrivateKey *rsa.PrivateKey
+		oldKey, newKey               schema.UaaKey
+	)
+
+	makeTokenWithKeyId := func(privateKey *rsa.PrivateKey, kid string) string {
+		return "bearer " + signClaims(jwt.SigningMethodRS256, privateKey, kid, tokenClaims())
+	}
+
+	BeforeEach(func() {
+		startUaaServer()
+
+		var err error
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+
+		var oldPublicKey, newPublicKey *rsa.PublicKey
+		oldPrivateKey, oldPublicKey, err = generateRSAKeyPair()
+		Expect(err).NotTo(HaveOccurred())
+		newPrivateKey, newPublicKey, err = generateRSAKeyPair()
+		Expect(err).NotTo(HaveOccurred())
+
+		oldPEM, err := publicKeyToPEM(oldPublicKey)
+		Expect(err).NotTo(HaveOccurred())
+		oldKey = schema.UaaKey{Kid: "key-1", Kty: "RSA", Alg: "RS256", Use: "sig", Value: string(oldPEM)}
+
+		newKey = schema.UaaKey{
+			Kid: "key-2",
+			Kty: "RSA",
+			Alg: "RS256",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(newPublicKey.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(newPublicKey.E)).Bytes()),
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("when UAA serves /token_keys", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				getJSONWebKeySetFetchHandler(oldKey, newKey),
+			)
+		})
+
+		It("returns every key", func() {
+			keySet, err := client.FetchKeySet()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keySet.Keys).To(Equal([]schema.UaaKey{oldKey, newKey}))
+			Expect(logger).To(gbytes.Say("fetch-key-set-starting"))
+			Expect(logger).To(gbytes.Say("fetch-key-set-successful"))
+		})
+
+		It("verifies tokens signed by any key in the set without refetching", func() {
+			server.AppendHandlers(getIssuerFetchHandler())
+
+			Expect(client.DecodeToken(makeTokenWithKeyId(newPrivateKey, "key-2"), "some.scope")).To(Succeed())
+			Expect(client.DecodeToken(makeTokenWithKeyId(oldPrivateKey, "key-1"), "some.scope")).To(Succeed())
+			Expect(client.DecodeToken(makeTokenWithKeyId(newPrivateKey, "key-2"), "some.scope")).To(Succeed())
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("refetches the key set when the token key id is unknown", func() {
+			server.AppendHandlers(
+				getIssuerFetchHandler(),
+				getJSONWebKeySetFetchHandler(oldKey, newKey),
+			)
+
+			err := client.DecodeToken(makeTokenWithKeyId(newPrivateKey, "key-3"), "some.scope")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("no verification key matches the token key id"))
+			Expect(server.ReceivedRequests()).To(HaveLen(3))
+		})
+	})
+
+	Context("when UAA does not serve /token_keys", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", TokenKeysEndpoint),
+					ghttp.RespondWith(http.StatusNotFound, ""),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", TokenKeyEndpoint),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, schema.UaaKey{Alg: "RS256", Value: oldKey.Value}),
+				),
+			)
+		})
+
+		It("falls back to the legacy /token_key endpoint", func() {
+			keySet, err := client.FetchKeySet()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keySet.Keys).To(HaveLen(1))
+			Expect(keySet.Keys[0].Value).To(Equal(oldKey.Value))
+			Expect(logger).To(gbytes.Say("fetch-key-set-falling-back-to-token-key"))
+		})
+
+		It("verifies tokens with the legacy key", func() {
+			server.AppendHandlers(getIssuerFetchHandler())
+
+			Expect(client.DecodeToken(makeTokenWithKeyId(oldPrivateKey, "key-1"), "some.scope")).To(Succeed())
+			Expect(server.ReceivedRequests()).To(HaveLen(3))
+		})
+	})
+
+	Context("when UAA returns an error", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusInternalServerError, "booom"),
+			)
+		})
+
+		It("returns the error without falling back", func() {
+			_, err := client.FetchKeySet()
+			Expect(err).To(MatchError("http error: status code: 500"))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	Context("when no key in the set is usable", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusOK, `{"keys":[{"kid":"key-1","kty":"oct","alg":"HS256"}]}`),
+			)
+		})
+
+		It("returns an error", func() {
+			_, err := client.FetchKeySet()
+			Expect(err).To(MatchError("UAA did not return any usable verification keys"))
+			Expect(logger).To(gbytes.Say("skipping-unusable-verification-key"))
+		})
+	})
+})
@@ -0,0 +1,150 @@
+package uaa_go_client_test
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/config"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("FetchTokenForScopes", func() {
+	var (
+		client uaa_go_client.Client
+	)
+
+	getScopedOauthHandlerFunc := func(expectedBody string, token *schema.Token) http.HandlerFunc {
+		return ghttp.CombineHandlers(
+			ghttp.VerifyRequest("POST", "/oauth/token"),
+			ghttp.VerifyBasicAuth("client-name", "client-secret"),
+			verifyBody(expectedBody),
+			ghttp.RespondWithJSONEncoded(http.StatusOK, token),
+		)
+	}
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			MaxNumberOfRetries:    DefaultMaxNumberOfRetries,
+			RetryInterval:         DefaultRetryInterval,
+			ExpirationBufferInSec: DefaultExpirationBufferTime,
+			RequestTimeout:        DefaultRequestTimeout,
+		}
+		server = ghttp.NewServer()
+
+		url, err := url.Parse(server.URL())
+		Expect(err).ToNot(HaveOccurred())
+		cfg.UaaEndpoint = "http://" + url.Host
+
+		cfg.ClientName = "client-name"
+		cfg.ClientSecret = "client-secret"
+		clock = fakeclock.NewFakeClock(time.Now())
+		logger = lagertest.NewTestLogger("test")
+
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("requests a client credentials token narrowed to the scopes", func() {
+		server.AppendHandlers(
+			getScopedOauthHandlerFunc("grant_type=client_credentials&scope=routing.routes.read", &schema.Token{AccessToken: "read token", ExpiresIn: 3600}),
+		)
+
+		token, err := client.FetchTokenForScopes([]string{"routing.routes.read"}, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("read token"))
+	})
+
+	It("caches a token for each distinct scope set", func() {
+		server.AppendHandlers(
+			getScopedOauthHandlerFunc("grant_type=client_credentials&scope=routing.routes.read", &schema.Token{AccessToken: "read token", ExpiresIn: 3600}),
+			getScopedOauthHandlerFunc("grant_type=client_credentials&scope=routing.routes.write", &schema.Token{AccessToken: "write token", ExpiresIn: 3600}),
+			getScopedOauthHandlerFunc("grant_type=client_credentials", &schema.Token{AccessToken: "full token", ExpiresIn: 3600}),
+		)
+
+		token, err := client.FetchTokenForScopes([]string{"routing.routes.read"}, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("read token"))
+
+		token, err = client.FetchTokenForScopes([]string{"routing.routes.write"}, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("write token"))
+
+		token, err = client.FetchToken(false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("full token"))
+
+		token, err = client.FetchTokenForScopes([]string{"routing.routes.read"}, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("read token"))
+
+		token, err = client.FetchTokenForScopes([]string{"routing.routes.write"}, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("write token"))
+		Expect(server.ReceivedRequests()).To(HaveLen(3))
+	})
+
+	It("treats scope sets with the same scopes in a different order as one entry", func() {
+		server.AppendHandlers(
+			getScopedOauthHandlerFunc("grant_type=client_credentials&scope=routing.routes.read+routing.routes.write", &schema.Token{AccessToken: "rw token", ExpiresIn: 3600}),
+		)
+
+		_, err := client.FetchTokenForScopes([]string{"routing.routes.write", "routing.routes.read"}, false)
+		Expect(err).NotTo(HaveOccurred())
+
+		token, err := client.FetchTokenForScopes([]string{"routing.routes.read", "routing.routes.write", "routing.routes.read"}, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("rw token"))
+		Expect(server.ReceivedRequests()).To(HaveLen(1))
+	})
+
+	It("expires each scope set independently", func() {
+		server.AppendHandlers(
+			getScopedOauthHandlerFunc("grant_type=client_credentials&scope=routing.routes.read", &schema.Token{AccessToken: "short lived", ExpiresIn: 60}),
+			getScopedOauthHandlerFunc("grant_type=client_credentials&scope=routing.routes.write", &schema.Token{AccessToken: "long lived", ExpiresIn: 3600}),
+			getScopedOauthHandlerFunc("grant_type=client_credentials&scope=routing.routes.read", &schema.Token{AccessToken: "renewed", ExpiresIn: 60}),
+		)
+
+		_, err := client.FetchTokenForScopes([]string{"routing.routes.read"}, false)
+		Expect(err).NotTo(HaveOccurred())
+		_, err = client.FetchTokenForScopes([]string{"routing.routes.write"}, false)
+		Expect(err).NotTo(HaveOccurred())
+
+		clock.Increment((60 - DefaultExpirationBufferTime) * time.Second)
+
+		token, err := client.FetchTokenForScopes([]string{"routing.routes.read"}, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("renewed"))
+
+		token, err = client.FetchTokenForScopes([]string{"routing.routes.write"}, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("long lived"))
+		Expect(server.ReceivedRequests()).To(HaveLen(3))
+	})
+
+	It("bypasses the cache when forcing an update", func() {
+		server.AppendHandlers(
+			getScopedOauthHandlerFunc("grant_type=client_credentials&scope=routing.routes.read", &schema.Token{AccessToken: "first", ExpiresIn: 3600}),
+			getScopedOauthHandlerFunc("grant_type=client_credentials&scope=routing.routes.read", &schema.Token{AccessToken: "second", ExpiresIn: 3600}),
+		)
+
+		_, err := client.FetchTokenForScopes([]string{"routing.routes.read"}, false)
+		Expect(err).NotTo(HaveOccurred())
+
+		token, err := client.FetchTokenForScopes([]string{"routing.routes.read"}, true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("second"))
+	})
+})
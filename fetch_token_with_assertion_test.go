@@ -0,0 +1,128 @@
+package uaa_go_client_test
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/config"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("FetchTokenWithAssertion", func() {
+	var (
+		client uaa_go_client.Client
+	)
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			MaxNumberOfRetries:    DefaultMaxNumberOfRetries,
+			RetryInterval:         DefaultRetryInterval,
+			ExpirationBufferInSec: DefaultExpirationBufferTime,
+			RequestTimeout:        DefaultRequestTimeout,
+		}
+		server = ghttp.NewServer()
+
+		url, err := url.Parse(server.URL())
+		Expect(err).ToNot(HaveOccurred())
+		cfg.UaaEndpoint = "http://" + url.Host
+
+		cfg.ClientName = "client-name"
+		cfg.ClientSecret = "client-secret"
+		clock = fakeclock.NewFakeClock(time.Now())
+		logger = lagertest.NewTestLogger("test")
+
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("when UAA returns 200 OK", func() {
+		It("performs a jwt-bearer grant with the assertion and scopes", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/oauth/token"),
+					ghttp.VerifyBasicAuth("client-name", "client-secret"),
+					ghttp.VerifyContentType("application/x-www-form-urlencoded; charset=UTF-8"),
+					verifyBody("assertion=idp.issued.jwt&grant_type=urn%3Aietf%3Aparams%3Aoauth%3Agrant-type%3Ajwt-bearer&scope=openid+routing.routes.read"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, &schema.Token{
+						AccessToken: "the token",
+						ExpiresIn:   20,
+					}),
+				),
+			)
+
+			token, err := client.FetchTokenWithAssertion("idp.issued.jwt", "openid", "routing.routes.read")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.AccessToken).To(Equal("the token"))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("omits the scope when none are requested", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					verifyBody("assertion=idp.issued.jwt&grant_type=urn%3Aietf%3Aparams%3Aoauth%3Agrant-type%3Ajwt-bearer"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, &schema.Token{AccessToken: "the token"}),
+				),
+			)
+
+			_, err := client.FetchTokenWithAssertion("idp.issued.jwt")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when the assertion is empty", func() {
+		It("returns an error without contacting UAA", func() {
+			_, err := client.FetchTokenWithAssertion("")
+			Expect(err).To(MatchError("Assertion cannot be empty"))
+			Expect(server.ReceivedRequests()).To(HaveLen(0))
+		})
+	})
+
+	Context("when UAA rejects the assertion", func() {
+		It("returns an error and doesn't retry", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusUnauthorized, "invalid assertion"),
+			)
+
+			_, err := client.FetchTokenWithAssertion("bad.jwt")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("status code: 401, body: invalid assertion"))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+
+	Context("when UAA returns a 5xx http status code", func() {
+		BeforeEach(func() {
+			cfg.MaxNumberOfRetries = 1
+		})
+
+		It("retries and finally returns an error", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusBadGateway, "bad gateway"),
+				ghttp.RespondWith(http.StatusServiceUnavailable, "unavailable"),
+			)
+
+			errChan := make(chan error, 1)
+			go func() {
+				_, err := client.FetchTokenWithAssertion("idp.issued.jwt")
+				errChan <- err
+			}()
+
+			clock.WaitForWatcherAndIncrement(DefaultRetryInterval)
+			Eventually(errChan).Should(Receive(MatchError("status code: 503, body: unavailable")))
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+	})
+})
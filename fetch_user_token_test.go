@@ -0,0 +1,157 @@
+package uaa_go_client_test
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/config"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("FetchUserToken", func() {
+	var (
+		client uaa_go_client.Client
+	)
+
+	getPasswordGrantHandlerFunc := func(status int, token *schema.Token, expectedBody string) http.HandlerFunc {
+		return ghttp.CombineHandlers(
+			ghttp.VerifyRequest("POST", "/oauth/token"),
+			ghttp.VerifyBasicAuth("client-name", "client-secret"),
+			ghttp.VerifyContentType("application/x-www-form-urlencoded; charset=UTF-8"),
+			verifyBody(expectedBody),
+			ghttp.RespondWithJSONEncoded(status, token),
+		)
+	}
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			MaxNumberOfRetries:    DefaultMaxNumberOfRetries,
+			RetryInterval:         DefaultRetryInterval,
+			ExpirationBufferInSec: DefaultExpirationBufferTime,
+			RequestTimeout:        DefaultRequestTimeout,
+		}
+		server = ghttp.NewServer()
+
+		url, err := url.Parse(server.URL())
+		Expect(err).ToNot(HaveOccurred())
+		cfg.UaaEndpoint = "http://" + url.Host
+
+		cfg.ClientName = "client-name"
+		cfg.ClientSecret = "client-secret"
+		clock = fakeclock.NewFakeClock(time.Now())
+		logger = lagertest.NewTestLogger("test")
+
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("when the client secret is missing from config", func() {
+		BeforeEach(func() {
+			cfg.ClientSecret = ""
+		})
+
+		It("returns an error without contacting UAA", func() {
+			_, err := client.FetchUserToken("user", "password")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("OAuth Client Secret cannot be empty"))
+			Expect(server.ReceivedRequests()).To(HaveLen(0))
+		})
+	})
+
+	Context("when UAA returns 200 OK", func() {
+		It("performs a password grant and returns the token", func() {
+			server.AppendHandlers(
+				getPasswordGrantHandlerFunc(http.StatusOK, &schema.Token{
+					AccessToken: "the user token",
+					ExpiresIn:   20,
+				}, "grant_type=password&password=p%40ss&username=user"),
+			)
+
+			token, err := client.FetchUserToken("user", "p@ss")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.ReceivedRequests()).Should(HaveLen(1))
+			Expect(token.AccessToken).To(Equal("the user token"))
+			Expect(token.ExpiresIn).To(Equal(int64(20)))
+		})
+
+		It("sends the login hint and narrowed scopes", func() {
+			server.AppendHandlers(
+				getPasswordGrantHandlerFunc(http.StatusOK, &schema.Token{
+					AccessToken: "the user token",
+				}, "grant_type=password&login_hint=%7B%22origin%22%3A%22ldap%22%7D&password=pass&scope=openid+cloud_controller.read&username=user"),
+			)
+
+			_, err := client.FetchUserToken("user", "pass",
+				uaa_go_client.WithLoginHint("ldap"),
+				uaa_go_client.WithScopes("openid", "cloud_controller.read"),
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.ReceivedRequests()).Should(HaveLen(1))
+		})
+
+		It("does not cache user tokens", func() {
+			server.AppendHandlers(
+				getPasswordGrantHandlerFunc(http.StatusOK, &schema.Token{AccessToken: "first", ExpiresIn: 3600}, "grant_type=password&password=pass&username=user"),
+				getPasswordGrantHandlerFunc(http.StatusOK, &schema.Token{AccessToken: "second", ExpiresIn: 3600}, "grant_type=password&password=pass&username=user"),
+			)
+
+			token, err := client.FetchUserToken("user", "pass")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.AccessToken).To(Equal("first"))
+
+			token, err = client.FetchUserToken("user", "pass")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.AccessToken).To(Equal("second"))
+			Expect(server.ReceivedRequests()).Should(HaveLen(2))
+		})
+	})
+
+	Context("when UAA rejects the credentials", func() {
+		It("returns an error and doesn't retry", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusUnauthorized, "bad credentials"),
+			)
+
+			_, err := client.FetchUserToken("user", "wrong")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("status code: 401, body: bad credentials"))
+			Expect(server.ReceivedRequests()).Should(HaveLen(1))
+		})
+	})
+
+	Context("when UAA returns a 5xx http status code", func() {
+		It("retries and returns the token", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusServiceUnavailable, "try again"),
+				getPasswordGrantHandlerFunc(http.StatusOK, &schema.Token{AccessToken: "the user token"}, "grant_type=password&password=pass&username=user"),
+			)
+
+			tokenChan := make(chan *schema.Token, 1)
+			go func() {
+				defer GinkgoRecover()
+				token, err := client.FetchUserToken("user", "pass")
+				Expect(err).NotTo(HaveOccurred())
+				tokenChan <- token
+			}()
+
+			clock.WaitForWatcherAndIncrement(DefaultRetryInterval)
+			Eventually(tokenChan).Should(Receive(WithTransform(func(t *schema.Token) string {
+				return t.AccessToken
+			}, Equal("the user token"))))
+			Expect(server.ReceivedRequests()).Should(HaveLen(2))
+		})
+	})
+})
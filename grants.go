@@ -0,0 +1,223 @@
+package uaa_go_client
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+
+	"code.cloudfoundry.org/uaa-go-client/schema"
+)
+
+const (
+	grantTypeJWTBearer     = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	grantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+)
+
+// TokenOption customizes the parameters sent to the UAA token and authorize
+// endpoints.
+type TokenOption func(url.Values)
+
+// WithScopes narrows the requested token to the given scopes.
+func WithScopes(scopes ...string) TokenOption {
+	return func(values url.Values) {
+		if len(scopes) > 0 {
+			values.Set("scope", strings.Join(scopes, " "))
+		}
+	}
+}
+
+// WithLoginHint selects the identity provider, by origin key, that UAA
+// authenticates the user against (e.g. "uaa" or "ldap").
+func WithLoginHint(origin string) TokenOption {
+	return func(values url.Values) {
+		hint, _ := json.Marshal(map[string]string{"origin": origin})
+		values.Set("login_hint", string(hint))
+	}
+}
+
+func (u *UaaClient) FetchUserToken(username, password string, opts ...TokenOption) (*schema.Token, error) {
+	logger := u.logger.Session("uaa-client")
+	tokenURL := u.tokenURL(logger)
+	logger.Debug("started-fetching-user-token", lager.Data{"endpoint": tokenURL, "username": username})
+
+	if err := u.config.CheckCredentials(); err != nil {
+		return nil, err
+	}
+
+	values := url.Values{}
+	values.Add("grant_type", "password")
+	values.Add("username", username)
+	values.Add("password", password)
+	for _, opt := range opts {
+		opt(values)
+	}
+
+	token, err := u.fetchTokenWithRetries(logger, values)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("successfully-fetched-user-token")
+	return token, nil
+}
+
+func (u *UaaClient) FetchPasscodeToken(passcode string, opts ...TokenOption) (*schema.Token, error) {
+	logger := u.logger.Session("uaa-client")
+	tokenURL := u.tokenURL(logger)
+	logger.Debug("started-fetching-passcode-token", lager.Data{"endpoint": tokenURL})
+
+	if err := u.config.CheckCredentials(); err != nil {
+		return nil, err
+	}
+
+	if passcode == "" {
+		return nil, errors.New("Passcode cannot be empty")
+	}
+
+	values := url.Values{}
+	values.Add("grant_type", "password")
+	values.Add("passcode", passcode)
+	for _, opt := range opts {
+		opt(values)
+	}
+
+	token, err := u.fetchTokenWithRetries(logger, values)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("successfully-fetched-passcode-token")
+	return token, nil
+}
+
+// FetchUserTokenForClient uses UAA's user_token grant to issue a token for
+// targetClientId on behalf of the user that owns userAccessToken. The request
+// is authenticated with userAccessToken rather than the client credentials.
+func (u *UaaClient) FetchUserTokenForClient(userAccessToken, targetClientId string, scopes ...string) (*schema.Token, error) {
+	logger := u.logger.Session("uaa-client")
+	tokenURL := u.tokenURL(logger)
+	logger.Debug("started-fetching-user-token-for-client", lager.Data{"endpoint": tokenURL, "target-client-id": targetClientId})
+
+	if userAccessToken == "" {
+		return nil, errors.New("User access token cannot be empty")
+	}
+
+	if targetClientId == "" {
+		return nil, errors.New("Target OAuth Client ID cannot be empty")
+	}
+
+	values := url.Values{}
+	values.Add("grant_type", "user_token")
+	values.Add("client_id", targetClientId)
+	values.Add("response_type", "token")
+	WithScopes(scopes...)(values)
+
+	token, err := u.fetchTokenWithRetriesUsing(logger, newBearerAuthenticatedRequest(userAccessToken), values)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("successfully-fetched-user-token-for-client")
+	return token, nil
+}
+
+func (u *UaaClient) RefreshToken(refreshToken string) (*schema.Token, error) {
+	logger := u.logger.Session("uaa-client")
+	tokenURL := u.tokenURL(logger)
+	logger.Debug("started-refreshing-token", lager.Data{"endpoint": tokenURL})
+
+	if err := u.config.CheckCredentials(); err != nil {
+		return nil, err
+	}
+
+	if refreshToken == "" {
+		return nil, errors.New("Refresh token cannot be empty")
+	}
+
+	values := url.Values{}
+	values.Add("grant_type", "refresh_token")
+	values.Add("refresh_token", refreshToken)
+
+	token, err := u.fetchTokenWithRetries(logger, values)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("successfully-refreshed-token")
+	return token, nil
+}
+
+func (u *UaaClient) FetchTokenWithAssertion(assertion string, scopes ...string) (*schema.Token, error) {
+	logger := u.logger.Session("uaa-client")
+	tokenURL := u.tokenURL(logger)
+	logger.Debug("started-fetching-token-with-assertion", lager.Data{"endpoint": tokenURL})
+
+	if err := u.config.CheckCredentials(); err != nil {
+		return nil, err
+	}
+
+	if assertion == "" {
+		return nil, errors.New("Assertion cannot be empty")
+	}
+
+	values := url.Values{}
+	values.Add("grant_type", grantTypeJWTBearer)
+	values.Add("assertion", assertion)
+	WithScopes(scopes...)(values)
+
+	token, err := u.fetchTokenWithRetries(logger, values)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("successfully-fetched-token-with-assertion")
+	return token, nil
+}
+
+func (u *UaaClient) ExchangeToken(request *schema.TokenExchangeRequest) (*schema.Token, error) {
+	logger := u.logger.Session("uaa-client")
+	tokenURL := u.tokenURL(logger)
+	logger.Debug("started-exchanging-token", lager.Data{"endpoint": tokenURL})
+
+	if err := u.config.CheckCredentials(); err != nil {
+		return nil, err
+	}
+
+	if request == nil || request.SubjectToken == "" {
+		return nil, errors.New("Subject token cannot be empty")
+	}
+
+	values := url.Values{}
+	values.Add("grant_type", grantTypeTokenExchange)
+	values.Add("subject_token", request.SubjectToken)
+	values.Add("subject_token_type", tokenTypeOrDefault(request.SubjectTokenType))
+	if request.ActorToken != "" {
+		values.Add("actor_token", request.ActorToken)
+		values.Add("actor_token_type", tokenTypeOrDefault(request.ActorTokenType))
+	}
+	if request.RequestedTokenType != "" {
+		values.Add("requested_token_type", request.RequestedTokenType)
+	}
+	for _, audience := range request.Audience {
+		values.Add("audience", audience)
+	}
+	WithScopes(request.Scopes...)(values)
+
+	token, err := u.fetchTokenWithRetries(logger, values)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("successfully-exchanged-token", lager.Data{"issued-token-type": token.IssuedTokenType})
+	return token, nil
+}
+
+func tokenTypeOrDefault(tokenType string) string {
+	if tokenType == "" {
+		return schema.TokenTypeAccessToken
+	}
+	return tokenType
+}
@@ -0,0 +1,128 @@
+package uaa_go_client
+
+import (
+	"crypto"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/golang-jwt/jwt/v4"
+
+	"code.cloudfoundry.org/uaa-go-client/schema"
+)
+
+// VerifyIDToken validates an OpenID Connect ID token issued to this client.
+// nonce must be the value sent with the authorization request and cannot be
+// empty. The ID token must carry exp and iat claims. When the ID token carries
+// an at_hash and accessToken is not empty, the access token issued alongside
+// it is checked against that hash.
+func (u *UaaClient) VerifyIDToken(idToken, nonce, accessToken string) (*schema.IDTokenClaims, error) {
+	logger := u.logger.Session("uaa-client")
+	logger.Debug("verify-id-token-started")
+	defer logger.Debug("verify-id-token-completed")
+
+	if idToken == "" {
+		return nil, errors.New("ID token cannot be empty")
+	}
+
+	if nonce == "" {
+		return nil, errors.New("Nonce cannot be empty")
+	}
+
+	if u.config.ClientName == "" {
+		return nil, errors.New("OAuth Client ID cannot be empty")
+	}
+
+	token, err := u.verifySignedToken(logger, idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := idTokenClaims(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.ExpiresAt == 0 {
+		logger.Info("verify-id-token-missing-exp")
+		return nil, errors.New("ID token has no exp claim")
+	}
+
+	if claims.IssuedAt == 0 {
+		logger.Info("verify-id-token-missing-iat")
+		return nil, errors.New("ID token has no iat claim")
+	}
+
+	if !containsString(claims.Audience, u.config.ClientName) {
+		logger.Info("verify-id-token-invalid-audience", lager.Data{"audience": claims.Audience})
+		return nil, errors.New("ID token audience does not include the client")
+	}
+
+	if (claims.Azp != "" || len(claims.Audience) > 1) && claims.Azp != u.config.ClientName {
+		logger.Info("verify-id-token-invalid-azp", lager.Data{"azp": claims.Azp})
+		return nil, errors.New("ID token was not issued to the client")
+	}
+
+	if claims.Nonce != nonce {
+		logger.Info("verify-id-token-invalid-nonce")
+		return nil, errors.New("ID token nonce does not match")
+	}
+
+	if claims.AtHash != "" && accessToken != "" {
+		if !validAccessTokenHash(token.Method, accessToken, claims.AtHash) {
+			logger.Info("verify-id-token-invalid-at-hash")
+			return nil, errors.New("ID token at_hash does not match the access token")
+		}
+	}
+
+	return claims, nil
+}
+
+func idTokenClaims(token *jwt.Token) (*schema.IDTokenClaims, error) {
+	data, err := json.Marshal(token.Claims)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &schema.IDTokenClaims{}
+	err = json.Unmarshal(data, claims)
+	if err != nil {
+		return nil, errors.New("Invalid token claims: " + err.Error())
+	}
+	return claims, nil
+}
+
+// validAccessTokenHash checks at_hash as defined by OpenID Connect Core
+// 3.1.3.6: the left half of the access token hash, using the hash of the ID
+// token signing algorithm.
+func validAccessTokenHash(method jwt.SigningMethod, accessToken, atHash string) bool {
+	var hash crypto.Hash
+	switch {
+	case strings.HasSuffix(method.Alg(), "256"):
+		hash = crypto.SHA256
+	case strings.HasSuffix(method.Alg(), "384"):
+		hash = crypto.SHA384
+	case strings.HasSuffix(method.Alg(), "512"):
+		hash = crypto.SHA512
+	default:
+		return false
+	}
+
+	h := hash.New()
+	h.Write([]byte(accessToken))
+	sum := h.Sum(nil)
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2]) == atHash
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+package uaa_go_client_test
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"time"
+
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+	"github.com/golang-jwt/jwt/v4"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("VerifyIDToken", func() {
+	const accessToken = "some-access-token"
+
+	var (
+		client     uaa_go_client.Client
+		privateKey *rsa.PrivateKey
+		claims     jwt.MapClaims
+	)
+
+	idToken := func(claims jwt.MapClaims) string {
+		return signClaims(jwt.SigningMethodRS256, privateKey, "some-key-id", claims)
+	}
+
+	accessTokenHash := func(accessToken string) string {
+		sum := sha256.Sum256([]byte(accessToken))
+		return base64.RawURLEncoding.EncodeToString(sum[:16])
+	}
+
+	BeforeEach(func() {
+		startUaaServer()
+
+		var (
+			publicKey *rsa.PublicKey
+			err       error
+		)
+		privateKey, publicKey, err = generateRSAKeyPair()
+		Expect(err).NotTo(HaveOccurred())
+
+		server.AppendHandlers(
+			getJSONWebKeySetFetchHandler(rsaVerificationKey("some-key-id", publicKey)),
+			getIssuerFetchHandler(),
+		)
+
+		claims = jwt.MapClaims{
+			"sub":       "user-guid",
+			"iss":       "https://uaa.domain.com",
+			"aud":       []string{"client-name"},
+			"exp":       clock.Now().Add(time.Hour).Unix(),
+			"iat":       clock.Now().Unix(),
+			"nonce":     "some-nonce",
+			"at_hash":   accessTokenHash(accessToken),
+			"user_name": "marissa",
+		}
+
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("returns the OpenID Connect claims", func() {
+		claims["azp"] = "client-name"
+		claims["email"] = "marissa@example.com"
+		claims["email_verified"] = true
+		claims["given_name"] = "Marissa"
+		claims["family_name"] = "Bloggs"
+		claims["phone_number"] = "555-1234"
+		claims["amr"] = []string{"pwd"}
+		claims["auth_time"] = 1481253000
+		claims["origin"] = "uaa"
+		claims["zid"] = "uaa"
+		claims["user_id"] = "user-guid"
+		claims["acr"] = map[string]interface{}{"values": []string{"urn:oasis:names:tc:SAML:2.0:ac:classes:Password"}}
+
+		idTokenClaims, err := client.VerifyIDToken(idToken(claims), "some-nonce", accessToken)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(idTokenClaims).To(Equal(&schema.IDTokenClaims{
+			Subject:       "user-guid",
+			Issuer:        "https://uaa.domain.com",
+			Audience:      []string{"client-name"},
+			ExpiresAt:     claims["exp"].(int64),
+			IssuedAt:      claims["iat"].(int64),
+			AuthTime:      1481253000,
+			Nonce:         "some-nonce",
+			Azp:           "client-name",
+			AtHash:        accessTokenHash(accessToken),
+			Amr:           []string{"pwd"},
+			UserId:        "user-guid",
+			UserName:      "marissa",
+			Email:         "marissa@example.com",
+			EmailVerified: true,
+			GivenName:     "Marissa",
+			FamilyName:    "Bloggs",
+			PhoneNumber:   "555-1234",
+			Origin:        "uaa",
+			ZoneId:        "uaa",
+			Extra: map[string]interface{}{
+				"acr": map[string]interface{}{"values": []interface{}{"urn:oasis:names:tc:SAML:2.0:ac:classes:Password"}},
+			},
+		}))
+	})
+
+	It("rejects an empty ID token", func() {
+		_, err := client.VerifyIDToken("", "some-nonce", accessToken)
+		Expect(err).To(MatchError("ID token cannot be empty"))
+	})
+
+	It("rejects ID tokens signed with an unknown key", func() {
+		otherPrivateKey, _, err := generateRSAKeyPair()
+		Expect(err).NotTo(HaveOccurred())
+		privateKey = otherPrivateKey
+		server.AppendHandlers(getJSONWebKeySetFetchHandler())
+
+		_, err = client.VerifyIDToken(idToken(claims), "some-nonce", accessToken)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects ID tokens from another issuer", func() {
+		claims["iss"] = "https://other.domain.com"
+
+		_, err := client.VerifyIDToken(idToken(claims), "some-nonce", accessToken)
+		Expect(err).To(MatchError("invalid issuer"))
+	})
+
+	It("rejects expired ID tokens", func() {
+		claims["exp"] = clock.Now().Add(-time.Minute).Unix()
+
+		_, err := client.VerifyIDToken(idToken(claims), "some-nonce", accessToken)
+		Expect(err).To(MatchError("Token is expired"))
+	})
+
+	It("rejects ID tokens without an expiry", func() {
+		delete(claims, "exp")
+
+		_, err := client.VerifyIDToken(idToken(claims), "some-nonce", accessToken)
+		Expect(err).To(MatchError("ID token has no exp claim"))
+	})
+
+	It("rejects ID tokens without an issue time", func() {
+		delete(claims, "iat")
+
+		_, err := client.VerifyIDToken(idToken(claims), "some-nonce", accessToken)
+		Expect(err).To(MatchError("ID token has no iat claim"))
+	})
+
+	It("rejects ID tokens issued to another client", func() {
+		claims["aud"] = "other-client"
+
+		_, err := client.VerifyIDToken(idToken(claims), "some-nonce", accessToken)
+		Expect(err).To(MatchError("ID token audience does not include the client"))
+	})
+
+	Context("when the ID token has several audiences", func() {
+		BeforeEach(func() {
+			claims["aud"] = []string{"client-name", "other-client"}
+		})
+
+		It("requires azp to be the client", func() {
+			_, err := client.VerifyIDToken(idToken(claims), "some-nonce", accessToken)
+			Expect(err).To(MatchError("ID token was not issued to the client"))
+
+			claims["azp"] = "client-name"
+			_, err = client.VerifyIDToken(idToken(claims), "some-nonce", accessToken)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	It("rejects ID tokens authorized for another client", func() {
+		claims["azp"] = "other-client"
+
+		_, err := client.VerifyIDToken(idToken(claims), "some-nonce", accessToken)
+		Expect(err).To(MatchError("ID token was not issued to the client"))
+	})
+
+	It("rejects ID tokens with a different nonce", func() {
+		_, err := client.VerifyIDToken(idToken(claims), "other-nonce", accessToken)
+		Expect(err).To(MatchError("ID token nonce does not match"))
+	})
+
+	It("rejects ID tokens without the expected nonce", func() {
+		delete(claims, "nonce")
+
+		_, err := client.VerifyIDToken(idToken(claims), "some-nonce", accessToken)
+		Expect(err).To(MatchError("ID token nonce does not match"))
+	})
+
+	It("requires a nonce", func() {
+		delete(claims, "nonce")
+
+		_, err := client.VerifyIDToken(idToken(claims), "", accessToken)
+		Expect(err).To(MatchError("Nonce cannot be empty"))
+	})
+
+	It("rejects ID tokens issued with a different access token", func() {
+		_, err := client.VerifyIDToken(idToken(claims), "some-nonce", "other-access-token")
+		Expect(err).To(MatchError("ID token at_hash does not match the access token"))
+	})
+
+	It("skips the at_hash check without an access token", func() {
+		_, err := client.VerifyIDToken(idToken(claims), "some-nonce", "")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("ignores the configured access token audiences", func() {
+		cfg.Audiences = []string{"routing"}
+
+		_, err := client.VerifyIDToken(idToken(claims), "some-nonce", accessToken)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,71 @@
+package uaa_go_client
+
+import (
+	"net/http"
+	"sync"
+
+	"code.cloudfoundry.org/uaa-go-client/schema"
+)
+
+const (
+	identityZoneIdHeader        = "X-Identity-Zone-Id"
+	identityZoneSubdomainHeader = "X-Identity-Zone-Subdomain"
+)
+
+// zoneRegistry is shared by a client and all of its zone clients so that
+// every zone is served by exactly one set of token and key caches.
+type zoneRegistry struct {
+	lock    sync.Mutex
+	clients map[schema.IdentityZone]*UaaClient
+}
+
+// ForZone returns a client for the given identity zone. Zone clients share the
+// background key refresher of the client returned by NewClient, which keeps
+// their keys up to date; only that client can stop it with Close.
+func (u *UaaClient) ForZone(zone schema.IdentityZone) Client {
+	u.zones.lock.Lock()
+	defer u.zones.lock.Unlock()
+
+	if zoneClient, ok := u.zones.clients[zone]; ok {
+		return zoneClient
+	}
+
+	zoneClient := &UaaClient{
+		logger:          u.logger,
+		config:          u.config,
+		client:          u.client,
+		clock:           u.clock,
+		lock:            new(sync.Mutex),
+		cachedTokens:    map[string]*cachedToken{},
+		assertionSigner: u.assertionSigner,
+		pinnedKeys:      u.pinnedKeys,
+		keyRefetcher:    &keyRefetcher{},
+		issuer:          u.config.Issuer,
+		zone:            zone,
+		zones:           u.zones,
+	}
+	u.zones.clients[zone] = zoneClient
+	return zoneClient
+}
+
+func (r *zoneRegistry) snapshot() []*UaaClient {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	clients := make([]*UaaClient, 0, len(r.clients))
+	for _, client := range r.clients {
+		clients = append(clients, client)
+	}
+	return clients
+}
+
+// do sends a request to UAA, targeting the client's identity zone.
+func (u *UaaClient) do(request *http.Request) (*http.Response, error) {
+	if u.zone.Id != "" {
+		request.Header.Set(identityZoneIdHeader, u.zone.Id)
+	}
+	if u.zone.Subdomain != "" {
+		request.Header.Set(identityZoneSubdomainHeader, u.zone.Subdomain)
+	}
+	return u.client.Do(request)
+}
@@ -0,0 +1,193 @@
+package uaa_go_client_test
+
+import (
+	"crypto/rsa"
+	"net/http"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/config"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Identity zones", func() {
+	var (
+		client uaa_go_client.Client
+	)
+
+	verifyZoneHeaders := func(zoneId, zoneSubdomain string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("X-Identity-Zone-Id")).To(Equal(zoneId))
+			Expect(r.Header.Get("X-Identity-Zone-Subdomain")).To(Equal(zoneSubdomain))
+		}
+	}
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			MaxNumberOfRetries:    DefaultMaxNumberOfRetries,
+			RetryInterval:         DefaultRetryInterval,
+			ExpirationBufferInSec: DefaultExpirationBufferTime,
+			RequestTimeout:        DefaultRequestTimeout,
+		}
+		server = ghttp.NewServer()
+
+		url, err := url.Parse(server.URL())
+		Expect(err).ToNot(HaveOccurred())
+		cfg.UaaEndpoint = "http://" + url.Host
+
+		cfg.ClientName = "client-name"
+		cfg.ClientSecret = "client-secret"
+		clock = fakeclock.NewFakeClock(time.Now())
+		logger = lagertest.NewTestLogger("test")
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("when no zone is configured", func() {
+		It("does not send zone headers", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					verifyZoneHeaders("", ""),
+					getOauthHandlerFunc(http.StatusOK, &schema.Token{AccessToken: "the token"}),
+				),
+			)
+
+			_, err := client.FetchToken(true)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("when a zone is configured for the client", func() {
+		BeforeEach(func() {
+			cfg.IdentityZoneId = "zone-id"
+		})
+
+		It("sends the zone id header on token and key requests", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					verifyZoneHeaders("zone-id", ""),
+					getOauthHandlerFunc(http.StatusOK, &schema.Token{AccessToken: "the token"}),
+				),
+				ghttp.CombineHandlers(
+					verifyZoneHeaders("zone-id", ""),
+					getSuccessKeyFetchHandler(ValidPemPublicKey),
+				),
+				ghttp.CombineHandlers(
+					verifyZoneHeaders("zone-id", ""),
+					ghttp.RespondWith(http.StatusOK, `{"issuer":"https://zone.uaa.domain.com/oauth/token"}`),
+				),
+			)
+
+			_, err := client.FetchToken(true)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.FetchKey()
+			Expect(err).NotTo(HaveOccurred())
+			issuer, err := client.FetchIssuer()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(issuer).To(Equal("https://zone.uaa.domain.com/oauth/token"))
+		})
+	})
+
+	Describe("ForZone", func() {
+		It("sends the zone subdomain header", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					verifyZoneHeaders("", "tenant"),
+					getOauthHandlerFunc(http.StatusOK, &schema.Token{AccessToken: "tenant token"}),
+				),
+			)
+
+			token, err := client.ForZone(schema.IdentityZone{Subdomain: "tenant"}).FetchToken(true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.AccessToken).To(Equal("tenant token"))
+		})
+
+		It("keeps a separate token cache for each zone", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					verifyZoneHeaders("", ""),
+					getOauthHandlerFunc(http.StatusOK, &schema.Token{AccessToken: "default token", ExpiresIn: 3600}),
+				),
+				ghttp.CombineHandlers(
+					verifyZoneHeaders("zone-a", ""),
+					getOauthHandlerFunc(http.StatusOK, &schema.Token{AccessToken: "zone a token", ExpiresIn: 3600}),
+				),
+				ghttp.CombineHandlers(
+					verifyZoneHeaders("zone-b", ""),
+					getOauthHandlerFunc(http.StatusOK, &schema.Token{AccessToken: "zone b token", ExpiresIn: 3600}),
+				),
+			)
+
+			zoneA := schema.IdentityZone{Id: "zone-a"}
+			zoneB := schema.IdentityZone{Id: "zone-b"}
+			for i := 0; i < 2; i++ {
+				token, err := client.FetchToken(false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(token.AccessToken).To(Equal("default token"))
+
+				token, err = client.ForZone(zoneA).FetchToken(false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(token.AccessToken).To(Equal("zone a token"))
+
+				token, err = client.ForZone(zoneB).FetchToken(false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(token.AccessToken).To(Equal("zone b token"))
+			}
+			Expect(server.ReceivedRequests()).To(HaveLen(3))
+		})
+
+		It("returns the same client for the same zone", func() {
+			zone := schema.IdentityZone{Id: "zone-a"}
+			Expect(client.ForZone(zone)).To(BeIdenticalTo(client.ForZone(zone)))
+			Expect(client.ForZone(zone).ForZone(schema.IdentityZone{})).To(BeIdenticalTo(client))
+		})
+
+		Context("when decoding tokens", func() {
+			var privateKey *rsa.PrivateKey
+
+			BeforeEach(func() {
+				var (
+					publicKey *rsa.PublicKey
+					err       error
+				)
+				privateKey, publicKey, err = generateRSAKeyPair()
+				Expect(err).NotTo(HaveOccurred())
+
+				key := rsaVerificationKey("some-key-id", publicKey)
+				server.AppendHandlers(
+					ghttp.CombineHandlers(verifyZoneHeaders("zone-a", ""), getJSONWebKeySetFetchHandler(key)),
+					ghttp.CombineHandlers(verifyZoneHeaders("zone-a", ""), getIssuerFetchHandler()),
+					ghttp.CombineHandlers(verifyZoneHeaders("zone-b", ""), getJSONWebKeySetFetchHandler(key)),
+					ghttp.CombineHandlers(verifyZoneHeaders("zone-b", ""), getIssuerFetchHandler()),
+				)
+			})
+
+			It("keeps a separate key cache for each zone", func() {
+				validToken, err := makeValidToken(privateKey)
+				Expect(err).NotTo(HaveOccurred())
+
+				for i := 0; i < 2; i++ {
+					Expect(client.ForZone(schema.IdentityZone{Id: "zone-a"}).DecodeToken(validToken, "some.scope")).To(Succeed())
+					Expect(client.ForZone(schema.IdentityZone{Id: "zone-b"}).DecodeToken(validToken, "some.scope")).To(Succeed())
+				}
+				Expect(server.ReceivedRequests()).To(HaveLen(4))
+			})
+		})
+	})
+})
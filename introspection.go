@@ -0,0 +1,117 @@
+package uaa_go_client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"code.cloudfoundry.org/lager"
+	trace "code.cloudfoundry.org/trace-logger"
+
+	"code.cloudfoundry.org/uaa-go-client/schema"
+)
+
+// ErrTokenInactive is returned by VerifyToken when online introspection
+// reports that a token is no longer active, e.g. because it was revoked.
+var ErrTokenInactive = errors.New("Token is not active")
+
+// RequireIntrospection asks UAA whether the token is still active after it
+// has been verified offline.
+func RequireIntrospection() VerifyOption {
+	return func(o *verifyOptions) {
+		o.introspect = true
+	}
+}
+
+// IntrospectToken asks UAA whether an access token is active using the
+// /introspect endpoint, falling back to /check_token on older UAA versions.
+func (u *UaaClient) IntrospectToken(token string) (*schema.TokenIntrospection, error) {
+	logger := u.logger.Session("uaa-client")
+
+	if token == "" {
+		return nil, errors.New("Token cannot be empty")
+	}
+	if err := u.config.CheckCredentials(); err != nil {
+		return nil, err
+	}
+
+	values := url.Values{}
+	values.Add("token", token)
+
+	introspectURL := u.introspectURL(logger)
+	logger.Debug("introspect-token-starting", lager.Data{"endpoint": introspectURL})
+
+	statusCode, body, err := u.postAsClient(introspectURL, values)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode == http.StatusNotFound {
+		checkTokenURL := u.checkTokenURL(logger)
+		logger.Info("introspect-token-falling-back-to-check-token", lager.Data{"endpoint": checkTokenURL})
+
+		statusCode, body, err = u.postAsClient(checkTokenURL, values)
+		if err != nil {
+			return nil, err
+		}
+
+		// /check_token has no active flag and rejects inactive tokens.
+		if statusCode == http.StatusBadRequest {
+			logger.Debug("introspect-token-completed", lager.Data{"active": false})
+			return &schema.TokenIntrospection{Active: false}, nil
+		}
+		if statusCode == http.StatusOK {
+			body, err = markActive(body)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if statusCode != http.StatusOK {
+		return nil, fmt.Errorf("status code: %d, body: %s", statusCode, body)
+	}
+
+	introspection := &schema.TokenIntrospection{}
+	err = json.Unmarshal(body, introspection)
+	if err != nil {
+		return nil, errors.New("unmarshalling error: " + err.Error())
+	}
+
+	logger.Debug("introspect-token-completed", lager.Data{"active": introspection.Active})
+	return introspection, nil
+}
+
+func (u *UaaClient) postAsClient(endpoint string, values url.Values) (int, []byte, error) {
+	request, err := u.newClientAuthenticatedRequest(endpoint, values)
+	if err != nil {
+		return 0, nil, err
+	}
+	trace.DumpRequest(request)
+
+	resp, err := u.do(request)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	trace.DumpResponse(resp)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, body, nil
+}
+
+func markActive(body []byte) ([]byte, error) {
+	claims := map[string]interface{}{}
+	err := json.Unmarshal(body, &claims)
+	if err != nil {
+		return nil, errors.New("unmarshalling error: " + err.Error())
+	}
+	claims["active"] = true
+	return json.Marshal(claims)
+}
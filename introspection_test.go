@@ -0,0 +1,192 @@
+package uaa_go_client_test
+
+import (
+	"crypto/rsa"
+	"net/http"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/config"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Token introspection", func() {
+	var (
+		client uaa_go_client.Client
+	)
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			MaxNumberOfRetries:    DefaultMaxNumberOfRetries,
+			RetryInterval:         DefaultRetryInterval,
+			ExpirationBufferInSec: DefaultExpirationBufferTime,
+			RequestTimeout:        DefaultRequestTimeout,
+		}
+		server = ghttp.NewServer()
+
+		url, err := url.Parse(server.URL())
+		Expect(err).ToNot(HaveOccurred())
+		cfg.UaaEndpoint = "http://" + url.Host
+
+		cfg.ClientName = "client-name"
+		cfg.ClientSecret = "client-secret"
+		clock = fakeclock.NewFakeClock(time.Now())
+		logger = lagertest.NewTestLogger("test")
+
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("IntrospectToken", func() {
+		It("returns the active status and claims from /introspect", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/introspect"),
+					ghttp.VerifyBasicAuth("client-name", "client-secret"),
+					ghttp.VerifyContentType("application/x-www-form-urlencoded; charset=UTF-8"),
+					verifyBody("token=the-token"),
+					ghttp.RespondWith(http.StatusOK, `{"active":true,"client_id":"cf","user_id":"user-guid","scope":["openid"],"exp":2491253686}`),
+				),
+			)
+
+			introspection, err := client.IntrospectToken("the-token")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(introspection.Active).To(BeTrue())
+			Expect(introspection.Claims).To(Equal(&schema.Claims{
+				ClientId:  "cf",
+				UserId:    "user-guid",
+				Scope:     []string{"openid"},
+				ExpiresAt: 2491253686,
+			}))
+		})
+
+		It("reports inactive tokens", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusOK, `{"active":false}`),
+			)
+
+			introspection, err := client.IntrospectToken("the-token")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(introspection.Active).To(BeFalse())
+			Expect(introspection.Claims).To(BeNil())
+		})
+
+		Context("when UAA does not serve /introspect", func() {
+			BeforeEach(func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/introspect"),
+						ghttp.RespondWith(http.StatusNotFound, ""),
+					),
+				)
+			})
+
+			It("falls back to /check_token", func() {
+				server.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest("POST", "/check_token"),
+						ghttp.VerifyBasicAuth("client-name", "client-secret"),
+						verifyBody("token=the-token"),
+						ghttp.RespondWith(http.StatusOK, `{"client_id":"cf","scope":["openid"]}`),
+					),
+				)
+
+				introspection, err := client.IntrospectToken("the-token")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(introspection.Active).To(BeTrue())
+				Expect(introspection.Claims.ClientId).To(Equal("cf"))
+				Expect(logger).To(gbytes.Say("introspect-token-falling-back-to-check-token"))
+			})
+
+			It("treats a rejected token as inactive", func() {
+				server.AppendHandlers(
+					ghttp.RespondWith(http.StatusBadRequest, `{"error":"invalid_token","error_description":"Token has been revoked"}`),
+				)
+
+				introspection, err := client.IntrospectToken("the-token")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(introspection.Active).To(BeFalse())
+			})
+		})
+
+		It("returns an error when the client is not allowed to introspect", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusForbidden, "insufficient_scope"),
+			)
+
+			_, err := client.IntrospectToken("the-token")
+			Expect(err).To(MatchError("status code: 403, body: insufficient_scope"))
+		})
+
+		It("returns an error when the token is empty", func() {
+			_, err := client.IntrospectToken("")
+			Expect(err).To(MatchError("Token cannot be empty"))
+			Expect(server.ReceivedRequests()).To(HaveLen(0))
+		})
+	})
+
+	Describe("VerifyToken with RequireIntrospection", func() {
+		var (
+			token string
+		)
+
+		BeforeEach(func() {
+			var (
+				privateKey *rsa.PrivateKey
+				publicKey  *rsa.PublicKey
+				err        error
+			)
+			privateKey, publicKey, err = generateRSAKeyPair()
+			Expect(err).NotTo(HaveOccurred())
+
+			token, err = makeValidToken(privateKey)
+			Expect(err).NotTo(HaveOccurred())
+
+			server.AppendHandlers(
+				getJSONWebKeySetFetchHandler(rsaVerificationKey("some-key-id", publicKey)),
+				getIssuerFetchHandler(),
+			)
+		})
+
+		It("accepts tokens that are still active", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/introspect"),
+					verifyBody("token="+token[len("bearer "):]),
+					ghttp.RespondWith(http.StatusOK, `{"active":true}`),
+				),
+			)
+
+			_, err := client.VerifyToken(token, uaa_go_client.RequireIntrospection())
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rejects revoked tokens", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusOK, `{"active":false}`),
+			)
+
+			_, err := client.VerifyToken(token, uaa_go_client.RequireIntrospection())
+			Expect(err).To(Equal(uaa_go_client.ErrTokenInactive))
+		})
+
+		It("does not introspect unless asked to", func() {
+			_, err := client.VerifyToken(token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+	})
+})
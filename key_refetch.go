@@ -0,0 +1,85 @@
+package uaa_go_client
+
+import (
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// keyRefetcher guards the key fetches triggered on the request path, both
+// the first fetch and the refetches forced by tokens that do not verify.
+// Concurrent fetches share one request, and once a fetch comes back without
+// new keys (or fails) further fetches are skipped for MinKeyRefetchInterval,
+// so tokens with bad signatures fail fast instead of each costing a
+// round-trip to UAA.
+type keyRefetcher struct {
+	lock        sync.Mutex
+	inFlight    *keyRefetch
+	backOffTill time.Time
+	lastErr     error
+}
+
+type keyRefetch struct {
+	done chan struct{}
+	keys keySet
+	err  error
+}
+
+func (u *UaaClient) refetchKeys(logger lager.Logger, previousKeys keySet) (keySet, error) {
+	r := u.keyRefetcher
+
+	r.lock.Lock()
+	if call := r.inFlight; call != nil {
+		r.lock.Unlock()
+		logger.Debug("waiting-for-key-refetch")
+		<-call.done
+		return call.keys, call.err
+	}
+	if u.clock.Now().Before(r.backOffTill) {
+		lastErr := r.lastErr
+		r.lock.Unlock()
+		logger.Info("skipping-key-refetch", lager.Data{"until": r.backOffTill.String()})
+		if len(previousKeys) == 0 {
+			return nil, lastErr
+		}
+		return previousKeys, nil
+	}
+	call := &keyRefetch{done: make(chan struct{})}
+	r.inFlight = call
+	r.lock.Unlock()
+
+	logger.Debug("fetching-new-uaa-key")
+	_, call.err = u.FetchKeySet()
+	if call.err == nil {
+		call.keys = u.getKeySet()
+	}
+
+	unchanged := call.err != nil || previousKeys.equal(call.keys)
+	if unchanged {
+		logger.Debug("Fetched the same verification key from UAA")
+	} else {
+		logger.Debug("Fetched a different verification key from UAA")
+	}
+
+	r.lock.Lock()
+	r.inFlight = nil
+	r.lastErr = call.err
+	if unchanged {
+		r.backOffTill = u.clock.Now().Add(u.minKeyRefetchInterval())
+	}
+	r.lock.Unlock()
+	close(call.done)
+
+	return call.keys, call.err
+}
+
+func (u *UaaClient) minKeyRefetchInterval() time.Duration {
+	if u.config.MinKeyRefetchInterval < 0 {
+		return 0
+	}
+	if u.config.MinKeyRefetchInterval == 0 {
+		return DefaultMinKeyRefetchInterval
+	}
+	return u.config.MinKeyRefetchInterval
+}
@@ -0,0 +1,173 @@
+package uaa_go_client_test
+
+import (
+	"crypto/rsa"
+	"net/http"
+	"sync"
+	"time"
+
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Forced key refetches", func() {
+	var (
+		client uaa_go_client.Client
+
+		privateKey, otherPrivateKey *rsa.PrivateKey
+		key                         schema.UaaKey
+	)
+
+	signedToken := func(privateKey *rsa.PrivateKey) string {
+		token, err := makeValidToken(privateKey)
+		Expect(err).NotTo(HaveOccurred())
+		return token
+	}
+
+	BeforeEach(func() {
+		startUaaServer()
+		cfg.MinKeyRefetchInterval = time.Minute
+
+		var (
+			publicKey *rsa.PublicKey
+			err       error
+		)
+		privateKey, publicKey, err = generateRSAKeyPair()
+		Expect(err).NotTo(HaveOccurred())
+		otherPrivateKey, _, err = generateRSAKeyPair()
+		Expect(err).NotTo(HaveOccurred())
+		key = rsaVerificationKey("some-key-id", publicKey)
+
+		server.AppendHandlers(
+			getJSONWebKeySetFetchHandler(key),
+			getIssuerFetchHandler(),
+		)
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+
+		Expect(client.DecodeToken(signedToken(privateKey), "some.scope")).To(Succeed())
+		Expect(server.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("when a refetch returns the same keys", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(getJSONWebKeySetFetchHandler(key), getJSONWebKeySetFetchHandler(key))
+		})
+
+		It("does not refetch again until the interval has passed", func() {
+			badToken := signedToken(otherPrivateKey)
+
+			Expect(client.DecodeToken(badToken, "some.scope")).NotTo(Succeed())
+			Expect(server.ReceivedRequests()).To(HaveLen(3))
+
+			Expect(client.DecodeToken(badToken, "some.scope")).NotTo(Succeed())
+			Expect(client.DecodeToken(badToken, "some.scope")).NotTo(Succeed())
+			Expect(server.ReceivedRequests()).To(HaveLen(3))
+			Expect(logger).To(gbytes.Say("skipping-key-refetch"))
+
+			clock.Increment(time.Minute)
+
+			Expect(client.DecodeToken(badToken, "some.scope")).NotTo(Succeed())
+			Expect(server.ReceivedRequests()).To(HaveLen(4))
+		})
+
+		It("still verifies tokens signed with the known keys", func() {
+			Expect(client.DecodeToken(signedToken(otherPrivateKey), "some.scope")).NotTo(Succeed())
+			Expect(client.DecodeToken(signedToken(privateKey), "some.scope")).To(Succeed())
+			Expect(server.ReceivedRequests()).To(HaveLen(3))
+		})
+
+		Context("when the limit is disabled", func() {
+			BeforeEach(func() {
+				cfg.MinKeyRefetchInterval = -1
+			})
+
+			It("refetches for every token that fails to verify", func() {
+				badToken := signedToken(otherPrivateKey)
+
+				Expect(client.DecodeToken(badToken, "some.scope")).NotTo(Succeed())
+				Expect(client.DecodeToken(badToken, "some.scope")).NotTo(Succeed())
+				Expect(server.ReceivedRequests()).To(HaveLen(4))
+			})
+		})
+	})
+
+	Context("when a refetch fails", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(ghttp.RespondWith(http.StatusInternalServerError, "error"))
+		})
+
+		It("does not refetch again until the interval has passed", func() {
+			badToken := signedToken(otherPrivateKey)
+
+			Expect(client.DecodeToken(badToken, "some.scope")).To(MatchError("http error: status code: 500"))
+			Expect(client.DecodeToken(badToken, "some.scope")).NotTo(Succeed())
+			Expect(server.ReceivedRequests()).To(HaveLen(3))
+		})
+	})
+
+	Context("when a refetch returns new keys", func() {
+		var rotatedPrivateKey *rsa.PrivateKey
+
+		BeforeEach(func() {
+			var err error
+			rotatedPrivateKey, _, err = generateRSAKeyPair()
+			Expect(err).NotTo(HaveOccurred())
+
+			server.AppendHandlers(
+				getJSONWebKeySetFetchHandler(rsaVerificationKey("some-key-id", &otherPrivateKey.PublicKey)),
+				getJSONWebKeySetFetchHandler(rsaVerificationKey("some-key-id", &rotatedPrivateKey.PublicKey)),
+			)
+		})
+
+		It("allows another refetch straight away", func() {
+			Expect(client.DecodeToken(signedToken(otherPrivateKey), "some.scope")).To(Succeed())
+			Expect(server.ReceivedRequests()).To(HaveLen(3))
+
+			Expect(client.DecodeToken(signedToken(rotatedPrivateKey), "some.scope")).To(Succeed())
+			Expect(server.ReceivedRequests()).To(HaveLen(4))
+		})
+	})
+
+	It("shares a single in-flight refetch between concurrent requests", func() {
+		release := make(chan struct{})
+		server.AppendHandlers(ghttp.CombineHandlers(
+			func(w http.ResponseWriter, r *http.Request) {
+				<-release
+			},
+			getJSONWebKeySetFetchHandler(key),
+		))
+
+		badToken := signedToken(otherPrivateKey)
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				Expect(client.DecodeToken(badToken, "some.scope")).NotTo(Succeed())
+			}()
+		}
+
+		Eventually(server.ReceivedRequests).Should(HaveLen(3))
+		Consistently(server.ReceivedRequests).Should(HaveLen(3))
+		close(release)
+		wg.Wait()
+
+		Expect(server.ReceivedRequests()).To(HaveLen(3))
+	})
+})
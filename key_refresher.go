@@ -0,0 +1,94 @@
+package uaa_go_client
+
+import (
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// keyRefresher periodically refetches the verification keys of a client and
+// its zone clients so that key rotation does not happen on the request path.
+type keyRefresher struct {
+	interval time.Duration
+	stop     chan struct{}
+	lock     sync.Mutex
+}
+
+func newKeyRefresher(interval time.Duration) *keyRefresher {
+	return &keyRefresher{
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+func (r *keyRefresher) run(u *UaaClient) {
+	logger := u.logger.Session("uaa-client")
+	logger.Info("key-refresher-started", lager.Data{"interval": r.interval.String()})
+
+	for {
+		for _, client := range u.zones.snapshot() {
+			_, keys, err := client.fetchKeySet(logger)
+			if err != nil {
+				logger.Error("key-refresher-fetch-failed", err, lager.Data{"zone": client.zone})
+				continue
+			}
+			if !r.install(client, keys) {
+				logger.Info("key-refresher-stopped")
+				return
+			}
+			logger.Info("key-refresher-keys-updated", lager.Data{"zone": client.zone, "key-ids": keys.keyIds()})
+		}
+
+		// Only the clock is handed to the sleeping goroutine, so a closed
+		// client is not kept alive until the interval ends.
+		slept := make(chan struct{})
+		go func(c clock) {
+			c.Sleep(r.interval)
+			close(slept)
+		}(u.clock)
+
+		select {
+		case <-r.stop:
+			logger.Info("key-refresher-stopped")
+			return
+		case <-slept:
+		}
+	}
+}
+
+// install swaps in refreshed keys unless the refresher has been closed.
+func (r *keyRefresher) install(client *UaaClient, keys keySet) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	select {
+	case <-r.stop:
+		return false
+	default:
+	}
+
+	client.setKeySet(keys)
+	return true
+}
+
+func (r *keyRefresher) close() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	select {
+	case <-r.stop:
+	default:
+		close(r.stop)
+	}
+}
+
+// Close stops the background key refresher, if one is running. Keys are not
+// refreshed after Close returns. The refresher belongs to the client returned
+// by NewClient and serves all of its zone clients, so Close on a client
+// returned by ForZone does nothing.
+func (u *UaaClient) Close() {
+	if u.keyRefresher != nil {
+		u.keyRefresher.close()
+	}
+}
@@ -0,0 +1,123 @@
+package uaa_go_client_test
+
+import (
+	"crypto/rsa"
+	"net/http"
+	"time"
+
+	"code.cloudfoundry.org/lager/lagertest"
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+	"github.com/golang-jwt/jwt/v4"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Background key refresher", func() {
+	const refreshInterval = 10 * time.Minute
+
+	var (
+		client uaa_go_client.Client
+
+		oldPrivateKey, newPrivateKey *rsa.PrivateKey
+		oldKey, newKey               schema.UaaKey
+	)
+
+	makeKey := func(kid string) (*rsa.PrivateKey, schema.UaaKey) {
+		privateKey, publicKey, err := generateRSAKeyPair()
+		Expect(err).NotTo(HaveOccurred())
+		return privateKey, rsaVerificationKey(kid, publicKey)
+	}
+
+	signedToken := func(privateKey *rsa.PrivateKey, kid string) string {
+		return "bearer " + signClaims(jwt.SigningMethodRS256, privateKey, kid, tokenClaims())
+	}
+
+	BeforeEach(func() {
+		startUaaServer()
+		cfg.Issuer = "https://uaa.domain.com"
+		cfg.KeyRefreshInterval = refreshInterval
+
+		oldPrivateKey, oldKey = makeKey("key-1")
+		newPrivateKey, newKey = makeKey("key-2")
+
+		server.AppendHandlers(
+			getJSONWebKeySetFetchHandler(oldKey),
+			getJSONWebKeySetFetchHandler(oldKey, newKey),
+		)
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		client.Close()
+		server.Close()
+	})
+
+	It("fetches the keys when it starts and on every interval", func() {
+		Eventually(logger).Should(gbytes.Say("key-refresher-keys-updated"))
+		Expect(client.DecodeToken(signedToken(oldPrivateKey, "key-1"), "some.scope")).To(Succeed())
+
+		clock.WaitForWatcherAndIncrement(refreshInterval)
+		Eventually(logger).Should(gbytes.Say("key-refresher-keys-updated"))
+		Expect(client.DecodeToken(signedToken(newPrivateKey, "key-2"), "some.scope")).To(Succeed())
+		Expect(server.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("keeps the previous keys when a refresh fails", func() {
+		server.SetHandler(1, ghttp.RespondWith(http.StatusInternalServerError, "booom"))
+		Eventually(server.ReceivedRequests).Should(HaveLen(1))
+
+		clock.WaitForWatcherAndIncrement(refreshInterval)
+		Eventually(logger).Should(gbytes.Say("key-refresher-fetch-failed"))
+
+		Expect(client.DecodeToken(signedToken(oldPrivateKey, "key-1"), "some.scope")).To(Succeed())
+		Expect(server.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("stops refreshing as soon as Close is called", func() {
+		Eventually(logger).Should(gbytes.Say("key-refresher-keys-updated"))
+
+		client.Close()
+
+		Eventually(logger).Should(gbytes.Say("key-refresher-stopped"))
+		clock.Increment(refreshInterval)
+		Consistently(server.ReceivedRequests).Should(HaveLen(1))
+	})
+
+	It("keeps refreshing when a zone client is closed", func() {
+		server.AppendHandlers(getJSONWebKeySetFetchHandler(oldKey, newKey))
+		Eventually(logger).Should(gbytes.Say("key-refresher-keys-updated"))
+
+		client.ForZone(schema.IdentityZone{Id: "zone-a"}).Close()
+
+		clock.WaitForWatcherAndIncrement(refreshInterval)
+		Eventually(server.ReceivedRequests).Should(HaveLen(3))
+		Expect(string(logger.(*lagertest.TestLogger).Buffer().Contents())).NotTo(ContainSubstring("key-refresher-stopped"))
+	})
+
+	It("does not install keys fetched while Close was called", func() {
+		release := make(chan struct{})
+		server.SetHandler(0, ghttp.CombineHandlers(
+			func(w http.ResponseWriter, r *http.Request) {
+				<-release
+			},
+			getJSONWebKeySetFetchHandler(oldKey),
+		))
+		Eventually(server.ReceivedRequests).Should(HaveLen(1))
+
+		client.Close()
+		close(release)
+
+		Eventually(logger).Should(gbytes.Say("key-refresher-stopped"))
+		Expect(string(logger.(*lagertest.TestLogger).Buffer().Contents())).NotTo(ContainSubstring("key-refresher-keys-updated"))
+	})
+})
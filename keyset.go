@@ -0,0 +1,296 @@
+package uaa_go_client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/golang-jwt/jwt/v4"
+
+	"code.cloudfoundry.org/uaa-go-client/schema"
+)
+
+var errNoMatchingKey = errors.New("no verification key matches the token key id")
+
+type verificationKey struct {
+	uaaKey schema.UaaKey
+	key    interface{}
+}
+
+// keySet holds the parsed verification keys published by UAA.
+type keySet []*verificationKey
+
+// supports reports whether the key can verify tokens signed with method, so
+// that an RSA public key can never be used as an HMAC secret.
+func (k *verificationKey) supports(method jwt.SigningMethod) bool {
+	switch m := method.(type) {
+	case *jwt.SigningMethodHMAC:
+		_, ok := k.key.([]byte)
+		return ok
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		_, ok := k.key.(*rsa.PublicKey)
+		return ok
+	case *jwt.SigningMethodECDSA:
+		ecKey, ok := k.key.(*ecdsa.PublicKey)
+		return ok && ecKey.Curve.Params().BitSize == m.CurveBits
+	default:
+		return true
+	}
+}
+
+func (ks keySet) lookup(kid string) (*verificationKey, error) {
+	for _, key := range ks {
+		if key.uaaKey.Kid == kid {
+			return key, nil
+		}
+	}
+
+	// Tokens without a kid and keys without a kid (legacy /token_key) can
+	// only be paired unambiguously when there is a single key.
+	if len(ks) == 1 && (kid == "" || ks[0].uaaKey.Kid == "") {
+		return ks[0], nil
+	}
+
+	return nil, errNoMatchingKey
+}
+
+func (ks keySet) equal(other keySet) bool {
+	if len(ks) != len(other) {
+		return false
+	}
+	for i := range ks {
+		if ks[i].uaaKey != other[i].uaaKey {
+			return false
+		}
+	}
+	return true
+}
+
+func (ks keySet) keyIds() []string {
+	kids := make([]string, 0, len(ks))
+	for _, key := range ks {
+		kids = append(kids, key.uaaKey.Kid)
+	}
+	return kids
+}
+
+func newKeySet(logger lager.Logger, uaaKeys []schema.UaaKey, allowSymmetric bool) (keySet, error) {
+	keys := keySet{}
+	for _, uaaKey := range uaaKeys {
+		key, err := parseVerificationKey(uaaKey, allowSymmetric)
+		if err != nil {
+			logger.Info("skipping-unusable-verification-key", lager.Data{"kid": uaaKey.Kid, "error": err.Error()})
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("UAA did not return any usable verification keys")
+	}
+	return keys, nil
+}
+
+func parseVerificationKey(uaaKey schema.UaaKey, allowSymmetric bool) (*verificationKey, error) {
+	if isSymmetricAlg(uaaKey.Alg) {
+		if !allowSymmetric {
+			return nil, errors.New("symmetric signing keys are not allowed")
+		}
+		if uaaKey.Value == "" {
+			return nil, errors.New("symmetric signing key cannot be empty")
+		}
+		return &verificationKey{uaaKey: uaaKey, key: []byte(uaaKey.Value)}, nil
+	}
+
+	if uaaKey.Value != "" {
+		if err := checkPublicKey(uaaKey.Value); err != nil {
+			return nil, err
+		}
+
+		if rsaKey, err := jwt.ParseRSAPublicKeyFromPEM([]byte(uaaKey.Value)); err == nil {
+			return &verificationKey{uaaKey: uaaKey, key: rsaKey}, nil
+		}
+
+		ecKey, err := jwt.ParseECPublicKeyFromPEM([]byte(uaaKey.Value))
+		if err != nil {
+			return nil, errors.New("Public uaa token must be an RSA or EC public key")
+		}
+		return &verificationKey{uaaKey: uaaKey, key: ecKey}, nil
+	}
+
+	switch uaaKey.Kty {
+	case "RSA":
+		return parseRSAJSONWebKey(uaaKey)
+	case "EC":
+		return parseECJSONWebKey(uaaKey)
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", uaaKey.Kty)
+	}
+}
+
+func parseRSAJSONWebKey(uaaKey schema.UaaKey) (*verificationKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(uaaKey.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RSA modulus: %s", err.Error())
+	}
+
+	e, err := base64.RawURLEncoding.DecodeString(uaaKey.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RSA exponent: %s", err.Error())
+	}
+
+	pubKey := &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}
+	return &verificationKey{uaaKey: uaaKey, key: pubKey}, nil
+}
+
+func parseECJSONWebKey(uaaKey schema.UaaKey) (*verificationKey, error) {
+	var curve elliptic.Curve
+	switch uaaKey.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported EC curve: %s", uaaKey.Crv)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(uaaKey.X)
+	if err != nil {
+		return nil, fmt.Errorf("invalid EC x coordinate: %s", err.Error())
+	}
+
+	y, err := base64.RawURLEncoding.DecodeString(uaaKey.Y)
+	if err != nil {
+		return nil, fmt.Errorf("invalid EC y coordinate: %s", err.Error())
+	}
+
+	pubKey := &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}
+	if !curve.IsOnCurve(pubKey.X, pubKey.Y) {
+		return nil, errors.New("EC public key is not on its curve")
+	}
+	return &verificationKey{uaaKey: uaaKey, key: pubKey}, nil
+}
+
+func (u *UaaClient) FetchKeySet() (*schema.JSONWebKeySet, error) {
+	logger := u.logger.Session("uaa-client")
+	uaaKeySet, keys, err := u.fetchKeySet(logger)
+	if err != nil {
+		return nil, err
+	}
+	u.setKeySet(keys)
+
+	logger.Info("fetch-key-set-successful", lager.Data{"key-ids": keys.keyIds()})
+	return uaaKeySet, nil
+}
+
+// fetchKeySet reads the verification keys from /token_keys, falling back to
+// /token_key on older UAAs, without installing them.
+func (u *UaaClient) fetchKeySet(logger lager.Logger) (*schema.JSONWebKeySet, keySet, error) {
+	getKeysUrl := u.tokenKeysURL(logger)
+
+	logger.Info("fetch-key-set-starting", lager.Data{"endpoint": getKeysUrl})
+
+	request, err := u.newKeyRequest(getKeysUrl)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := u.do(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		logger.Info("fetch-key-set-falling-back-to-token-key")
+		uaaKey, keys, err := u.fetchLegacyKey(logger)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &schema.JSONWebKeySet{Keys: []schema.UaaKey{*uaaKey}}, keys, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("http error: status code: %d", resp.StatusCode)
+	}
+
+	uaaKeySet := &schema.JSONWebKeySet{}
+	err = json.NewDecoder(resp.Body).Decode(uaaKeySet)
+	if err != nil {
+		return nil, nil, errors.New("unmarshalling error: " + err.Error())
+	}
+
+	keys, err := newKeySet(logger, uaaKeySet.Keys, u.config.AllowSymmetricSigningKeys)
+	if err != nil {
+		return nil, nil, err
+	}
+	return uaaKeySet, keys, nil
+}
+
+// newKeyRequest builds a key fetch request. UAA only serves symmetric keys to
+// authenticated clients, so the client credentials are sent when they are
+// allowed.
+func (u *UaaClient) newKeyRequest(endpoint string) (*http.Request, error) {
+	request, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.config.AllowSymmetricSigningKeys {
+		if err := u.config.CheckCredentials(); err != nil {
+			return nil, err
+		}
+		request.SetBasicAuth(u.config.ClientName, u.config.ClientSecret)
+	}
+	return request, nil
+}
+
+func isSymmetricAlg(alg string) bool {
+	switch alg {
+	case "HS256", "HS384", "HS512":
+		return true
+	default:
+		return false
+	}
+}
+
+func (u *UaaClient) getVerificationKeys(logger lager.Logger, forceFetch bool) (keySet, error) {
+	if u.pinnedKeys != nil {
+		return u.pinnedKeys, nil
+	}
+
+	previousKeys := u.getKeySet()
+	if len(previousKeys) == 0 || forceFetch {
+		return u.refetchKeys(logger, previousKeys)
+	}
+
+	return previousKeys, nil
+}
+
+func (u *UaaClient) getKeySet() keySet {
+	u.rwlock.RLock()
+	defer u.rwlock.RUnlock()
+	return u.keys
+}
+
+func (u *UaaClient) setKeySet(keys keySet) {
+	u.rwlock.Lock()
+	defer u.rwlock.Unlock()
+	u.keys = keys
+}
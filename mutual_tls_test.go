@@ -0,0 +1,196 @@
+package uaa_go_client_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/config"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Mutual TLS", func() {
+	var (
+		caCert       *x509.Certificate
+		caPrivateKey *ecdsa.PrivateKey
+		tlsServer    *http.Server
+		certDir      string
+
+		lock           sync.Mutex
+		serialsSeen    []*big.Int
+		clientIdsSeen  []string
+		basicAuthsSeen []bool
+	)
+
+	writeClientCert := func(modTime time.Time) *big.Int {
+		cert, err := createCertificate(caCert, caPrivateKey, isClient)
+		Expect(err).NotTo(HaveOccurred())
+
+		keyBytes, err := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+		Expect(err).NotTo(HaveOccurred())
+
+		certFile := filepath.Join(certDir, "client.crt")
+		keyFile := filepath.Join(certDir, "client.key")
+		Expect(ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0600)).To(Succeed())
+		Expect(ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}), 0600)).To(Succeed())
+		Expect(os.Chtimes(certFile, modTime, modTime)).To(Succeed())
+		Expect(os.Chtimes(keyFile, modTime, modTime)).To(Succeed())
+
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		Expect(err).NotTo(HaveOccurred())
+		return leaf.SerialNumber
+	}
+
+	BeforeEach(func() {
+		var err error
+		serialsSeen = nil
+		clientIdsSeen = nil
+		basicAuthsSeen = nil
+
+		certDir, err = ioutil.TempDir("", "uaa-go-client-mtls")
+		Expect(err).NotTo(HaveOccurred())
+
+		caCert, caPrivateKey, err = createCA()
+		Expect(err).NotTo(HaveOccurred())
+
+		f, err := os.Create(filepath.Join(certDir, "ca.crt"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pem.Encode(f, &pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw})).To(Succeed())
+		Expect(f.Close()).To(Succeed())
+
+		serverCert, err := createCertificate(caCert, caPrivateKey, isServer)
+		Expect(err).NotTo(HaveOccurred())
+
+		clientCAs := x509.NewCertPool()
+		clientCAs.AddCert(caCert)
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		tlsListener := tls.NewListener(listener, &tls.Config{
+			Certificates: []tls.Certificate{serverCert},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    clientCAs,
+		})
+
+		tlsServer = &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _, hasBasicAuth := r.BasicAuth()
+			r.ParseForm()
+
+			lock.Lock()
+			serialsSeen = append(serialsSeen, r.TLS.PeerCertificates[0].SerialNumber)
+			clientIdsSeen = append(clientIdsSeen, r.PostForm.Get("client_id"))
+			basicAuthsSeen = append(basicAuthsSeen, hasBasicAuth)
+			lock.Unlock()
+
+			w.Header().Set("Connection", "close")
+			w.Write([]byte(`{"access_token":"bound token","expires_in":3600}`))
+		})}
+		go tlsServer.Serve(tlsListener)
+
+		cfg = &config.Config{
+			UaaEndpoint:           "https://" + listener.Addr().String(),
+			ClientName:            "client-name",
+			ClientAuthMethod:      config.ClientAuthTLS,
+			CACerts:               filepath.Join(certDir, "ca.crt"),
+			ClientCert:            filepath.Join(certDir, "client.crt"),
+			ClientKey:             filepath.Join(certDir, "client.key"),
+			ExpirationBufferInSec: DefaultExpirationBufferTime,
+			RequestTimeout:        DefaultRequestTimeout,
+		}
+		clock = fakeclock.NewFakeClock(time.Now())
+		logger = lagertest.NewTestLogger("test")
+	})
+
+	AfterEach(func() {
+		tlsServer.Close()
+		Expect(os.RemoveAll(certDir)).To(Succeed())
+	})
+
+	It("authenticates with the client certificate instead of a secret", func() {
+		serial := writeClientCert(time.Now())
+
+		client, err := uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+
+		token, err := client.FetchToken(true)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.AccessToken).To(Equal("bound token"))
+
+		lock.Lock()
+		defer lock.Unlock()
+		Expect(serialsSeen).To(Equal([]*big.Int{serial}))
+		Expect(clientIdsSeen).To(Equal([]string{"client-name"}))
+		Expect(basicAuthsSeen).To(Equal([]bool{false}))
+	})
+
+	It("reloads the client certificate when it is rotated on disk", func() {
+		firstSerial := writeClientCert(time.Now().Add(-time.Hour))
+
+		client, err := uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.FetchToken(true)
+		Expect(err).NotTo(HaveOccurred())
+
+		secondSerial := writeClientCert(time.Now())
+
+		_, err = client.FetchToken(true)
+		Expect(err).NotTo(HaveOccurred())
+
+		lock.Lock()
+		defer lock.Unlock()
+		Expect(serialsSeen).To(Equal([]*big.Int{firstSerial, secondSerial}))
+	})
+
+	It("keeps using the previous certificate when the rotated one is unreadable", func() {
+		serial := writeClientCert(time.Now().Add(-time.Hour))
+
+		client, err := uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(ioutil.WriteFile(cfg.ClientKey, []byte("garbage"), 0600)).To(Succeed())
+
+		_, err = client.FetchToken(true)
+		Expect(err).NotTo(HaveOccurred())
+
+		lock.Lock()
+		defer lock.Unlock()
+		Expect(serialsSeen).To(Equal([]*big.Int{serial}))
+	})
+
+	It("fails to create the client when the certificate cannot be loaded", func() {
+		_, err := uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("failed read client cert file"))
+	})
+
+	It("requires an https endpoint", func() {
+		cfg.UaaEndpoint = "http://127.0.0.1:1111"
+		_, err := uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).To(MatchError("Mutual TLS client authentication requires an https UAA endpoint"))
+	})
+
+	It("requires the certificate and key to be configured", func() {
+		cfg.ClientCert = ""
+		cfg.ClientKey = ""
+		client, err := uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = client.FetchToken(true)
+		Expect(err).To(MatchError("OAuth Client certificate and key cannot be empty"))
+	})
+})
@@ -12,15 +12,77 @@ func NewNoOpUaaClient() Client {
 func (c *NoOpUaaClient) FetchToken(useCachedToken bool) (*schema.Token, error) {
 	return &schema.Token{}, nil
 }
+func (c *NoOpUaaClient) FetchTokenForScopes(scopes []string, forceUpdate bool) (*schema.Token, error) {
+	return &schema.Token{}, nil
+}
+func (c *NoOpUaaClient) FetchTokenWithAssertion(assertion string, scopes ...string) (*schema.Token, error) {
+	return &schema.Token{}, nil
+}
+func (c *NoOpUaaClient) ExchangeToken(request *schema.TokenExchangeRequest) (*schema.Token, error) {
+	return &schema.Token{}, nil
+}
+func (c *NoOpUaaClient) FetchUserToken(username, password string, opts ...TokenOption) (*schema.Token, error) {
+	return &schema.Token{}, nil
+}
+func (c *NoOpUaaClient) FetchPasscodeToken(passcode string, opts ...TokenOption) (*schema.Token, error) {
+	return &schema.Token{}, nil
+}
+func (c *NoOpUaaClient) FetchUserTokenForClient(userAccessToken, targetClientId string, scopes ...string) (*schema.Token, error) {
+	return &schema.Token{}, nil
+}
+func (c *NoOpUaaClient) RefreshToken(refreshToken string) (*schema.Token, error) {
+	return &schema.Token{}, nil
+}
+func (c *NoOpUaaClient) AuthorizationCodeURL(redirectURI string, opts ...TokenOption) (*schema.AuthorizationRequest, error) {
+	return &schema.AuthorizationRequest{}, nil
+}
+func (c *NoOpUaaClient) ExchangeAuthorizationCode(code, codeVerifier, redirectURI string) (*schema.Token, error) {
+	return &schema.Token{}, nil
+}
 func (c *NoOpUaaClient) DecodeToken(uaaToken string, desiredPermissions ...string) error {
 	return nil
 }
+func (c *NoOpUaaClient) VerifyToken(uaaToken string, opts ...VerifyOption) (*schema.Claims, error) {
+	return &schema.Claims{}, nil
+}
+func (c *NoOpUaaClient) VerifyIDToken(idToken, nonce, accessToken string) (*schema.IDTokenClaims, error) {
+	return &schema.IDTokenClaims{}, nil
+}
+func (c *NoOpUaaClient) IntrospectToken(token string) (*schema.TokenIntrospection, error) {
+	return &schema.TokenIntrospection{}, nil
+}
+func (c *NoOpUaaClient) RevokeToken(tokenId string) error {
+	return nil
+}
+func (c *NoOpUaaClient) RevokeUserTokens(userId string) error {
+	return nil
+}
+func (c *NoOpUaaClient) RevokeClientTokens(clientId string) error {
+	return nil
+}
+func (c *NoOpUaaClient) RevokeUserClientTokens(userId, clientId string) error {
+	return nil
+}
+func (c *NoOpUaaClient) Close() {
+}
 func (c *NoOpUaaClient) FetchKey() (string, error) {
 	return "", nil
 }
+func (c *NoOpUaaClient) FetchKeySet() (*schema.JSONWebKeySet, error) {
+	return &schema.JSONWebKeySet{}, nil
+}
 func (c *NoOpUaaClient) FetchIssuer() (string, error) {
 	return "", nil
 }
+func (c *NoOpUaaClient) FetchOpenIDConfiguration(forceUpdate bool) (*schema.OpenIDConfiguration, error) {
+	return &schema.OpenIDConfiguration{}, nil
+}
+func (c *NoOpUaaClient) FetchUserInfo(accessToken string) (*schema.UserInfo, error) {
+	return &schema.UserInfo{}, nil
+}
 func (c *NoOpUaaClient) RegisterOauthClient(oauthClient *schema.OauthClient) (*schema.OauthClient, error) {
 	return oauthClient, nil
 }
+func (c *NoOpUaaClient) ForZone(zone schema.IdentityZone) Client {
+	return c
+}
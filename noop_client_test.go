@@ -37,6 +37,78 @@ var _ = Describe("NoopUaaClient", func() {
 		})
 	})
 
+	Context("FetchTokenForScopes", func() {
+		It("returns an empty access token", func() {
+			token, err := client.FetchTokenForScopes([]string{"some.scope"}, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.AccessToken).To(BeEmpty())
+		})
+	})
+
+	Context("FetchTokenWithAssertion", func() {
+		It("returns an empty access token", func() {
+			token, err := client.FetchTokenWithAssertion("assertion")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.AccessToken).To(BeEmpty())
+		})
+	})
+
+	Context("ExchangeToken", func() {
+		It("returns an empty access token", func() {
+			token, err := client.ExchangeToken(&schema.TokenExchangeRequest{SubjectToken: "subject"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.AccessToken).To(BeEmpty())
+		})
+	})
+
+	Context("FetchUserToken", func() {
+		It("returns an empty access token", func() {
+			token, err := client.FetchUserToken("user", "password")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.AccessToken).To(BeEmpty())
+		})
+	})
+
+	Context("FetchPasscodeToken", func() {
+		It("returns an empty access token", func() {
+			token, err := client.FetchPasscodeToken("passcode")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.AccessToken).To(BeEmpty())
+		})
+	})
+
+	Context("FetchUserTokenForClient", func() {
+		It("returns an empty access token", func() {
+			token, err := client.FetchUserTokenForClient("user-token", "other-client")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.AccessToken).To(BeEmpty())
+		})
+	})
+
+	Context("RefreshToken", func() {
+		It("returns an empty access token", func() {
+			token, err := client.RefreshToken("refresh-token")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.AccessToken).To(BeEmpty())
+		})
+	})
+
+	Context("AuthorizationCodeURL", func() {
+		It("returns an empty authorization request", func() {
+			request, err := client.AuthorizationCodeURL("https://example.com/callback")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(request.URL).To(BeEmpty())
+		})
+	})
+
+	Context("ExchangeAuthorizationCode", func() {
+		It("returns an empty access token", func() {
+			token, err := client.ExchangeAuthorizationCode("code", "verifier", "https://example.com/callback")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.AccessToken).To(BeEmpty())
+		})
+	})
+
 	Context("FetchKey", func() {
 		It("returns an empty token key", func() {
 			key, err := client.FetchKey()
@@ -45,6 +117,14 @@ var _ = Describe("NoopUaaClient", func() {
 		})
 	})
 
+	Context("FetchKeySet", func() {
+		It("returns an empty key set", func() {
+			keySet, err := client.FetchKeySet()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(keySet.Keys).To(BeEmpty())
+		})
+	})
+
 	Context("DecodeToken", func() {
 		It("returns an empty decode", func() {
 			decoded := client.DecodeToken("some token", "some perm")
@@ -52,6 +132,67 @@ var _ = Describe("NoopUaaClient", func() {
 		})
 	})
 
+	Context("VerifyToken", func() {
+		It("returns empty claims", func() {
+			claims, err := client.VerifyToken("some token", RequireAnyScope("some perm"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claims).To(Equal(&schema.Claims{}))
+		})
+	})
+
+	Context("VerifyIDToken", func() {
+		It("returns empty claims", func() {
+			claims, err := client.VerifyIDToken("some id token", "some nonce", "some token")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(claims).To(Equal(&schema.IDTokenClaims{}))
+		})
+	})
+
+	Context("FetchOpenIDConfiguration", func() {
+		It("returns an empty discovery document", func() {
+			discovery, err := client.FetchOpenIDConfiguration(true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(discovery).To(Equal(&schema.OpenIDConfiguration{}))
+		})
+	})
+
+	Context("FetchUserInfo", func() {
+		It("returns an empty profile", func() {
+			userInfo, err := client.FetchUserInfo("some token")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(userInfo).To(Equal(&schema.UserInfo{}))
+		})
+	})
+
+	Context("IntrospectToken", func() {
+		It("returns an empty introspection", func() {
+			introspection, err := client.IntrospectToken("some token")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(introspection).To(Equal(&schema.TokenIntrospection{}))
+		})
+	})
+
+	Context("Revoke", func() {
+		It("does nothing", func() {
+			Expect(client.RevokeToken("token-id")).To(Succeed())
+			Expect(client.RevokeUserTokens("user-id")).To(Succeed())
+			Expect(client.RevokeClientTokens("client-id")).To(Succeed())
+			Expect(client.RevokeUserClientTokens("user-id", "client-id")).To(Succeed())
+		})
+	})
+
+	Context("Close", func() {
+		It("does nothing", func() {
+			client.Close()
+		})
+	})
+
+	Context("ForZone", func() {
+		It("returns the no-op client", func() {
+			Expect(client.ForZone(schema.IdentityZone{Id: "zone-id"})).To(Equal(client))
+		})
+	})
+
 	Context("RegisterOauthClient", func() {
 		It("returns the given oauthClient", func() {
 			oauthClient := &schema.OauthClient{}
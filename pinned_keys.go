@@ -0,0 +1,44 @@
+package uaa_go_client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"code.cloudfoundry.org/lager"
+
+	"code.cloudfoundry.org/uaa-go-client/config"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+)
+
+// loadPinnedKeys reads the verification keys configured in
+// VerificationKeysFile. The file holds either a JSON Web Key Set, as served
+// by /token_keys, or a single PEM encoded public key.
+func loadPinnedKeys(logger lager.Logger, cfg *config.Config) (keySet, error) {
+	contents, err := ioutil.ReadFile(cfg.VerificationKeysFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed read verification keys file: %s", err.Error())
+	}
+
+	var uaaKeys []schema.UaaKey
+	if bytes.HasPrefix(bytes.TrimSpace(contents), []byte("{")) {
+		uaaKeySet := schema.JSONWebKeySet{}
+		err = json.Unmarshal(contents, &uaaKeySet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse verification keys file: %s", err.Error())
+		}
+		uaaKeys = uaaKeySet.Keys
+	} else {
+		uaaKeys = []schema.UaaKey{{Value: string(contents)}}
+	}
+
+	keys, err := newKeySet(logger, uaaKeys, cfg.AllowSymmetricSigningKeys)
+	if err != nil {
+		return nil, errors.New("Verification keys file does not contain any usable keys")
+	}
+
+	logger.Info("loaded-pinned-verification-keys", lager.Data{"key-ids": keys.keyIds()})
+	return keys, nil
+}
@@ -0,0 +1,180 @@
+package uaa_go_client_test
+
+import (
+	"crypto/rsa"
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/config"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+	"github.com/golang-jwt/jwt/v4"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Pinned verification keys", func() {
+	var (
+		client       uaa_go_client.Client
+		keyDir       string
+		privateKey   *rsa.PrivateKey
+		publicKeyPEM []byte
+	)
+
+	writeKeysFile := func(name string, contents []byte) string {
+		path := filepath.Join(keyDir, name)
+		Expect(ioutil.WriteFile(path, contents, 0600)).To(Succeed())
+		return path
+	}
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			MaxNumberOfRetries:    DefaultMaxNumberOfRetries,
+			RetryInterval:         DefaultRetryInterval,
+			ExpirationBufferInSec: DefaultExpirationBufferTime,
+			RequestTimeout:        DefaultRequestTimeout,
+			Issuer:                "https://uaa.domain.com",
+		}
+		server = ghttp.NewServer()
+
+		url, err := url.Parse(server.URL())
+		Expect(err).ToNot(HaveOccurred())
+		cfg.UaaEndpoint = "http://" + url.Host
+
+		cfg.ClientName = "client-name"
+		cfg.ClientSecret = "client-secret"
+		clock = fakeclock.NewFakeClock(time.Now())
+		logger = lagertest.NewTestLogger("test")
+
+		keyDir, err = ioutil.TempDir("", "uaa-go-client-keys")
+		Expect(err).NotTo(HaveOccurred())
+
+		var publicKey *rsa.PublicKey
+		privateKey, publicKey, err = generateRSAKeyPair()
+		Expect(err).NotTo(HaveOccurred())
+		publicKeyPEM, err = publicKeyToPEM(publicKey)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		Expect(os.RemoveAll(keyDir)).To(Succeed())
+	})
+
+	Context("with a JWKS file", func() {
+		BeforeEach(func() {
+			_, otherPublicKey, err := generateRSAKeyPair()
+			Expect(err).NotTo(HaveOccurred())
+			otherPEM, err := publicKeyToPEM(otherPublicKey)
+			Expect(err).NotTo(HaveOccurred())
+
+			keySet, err := json.Marshal(schema.JSONWebKeySet{Keys: []schema.UaaKey{
+				{Kid: "other-key-id", Alg: "RS256", Value: string(otherPEM)},
+				{Kid: "some-key-id", Alg: "RS256", Value: string(publicKeyPEM)},
+			}})
+			Expect(err).NotTo(HaveOccurred())
+			cfg.VerificationKeysFile = writeKeysFile("keys.json", keySet)
+		})
+
+		It("verifies tokens without contacting UAA", func() {
+			token, err := makeValidToken(privateKey)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.DecodeToken(token, "some.scope")).To(Succeed())
+			Expect(server.ReceivedRequests()).To(HaveLen(0))
+		})
+
+		It("does not refetch keys for tokens with an unknown signature", func() {
+			otherPrivateKey, _, err := generateRSAKeyPair()
+			Expect(err).NotTo(HaveOccurred())
+			token, err := makeValidToken(otherPrivateKey)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = client.DecodeToken(token, "some.scope")
+			Expect(err).To(HaveOccurred())
+			validationError, ok := err.(*jwt.ValidationError)
+			Expect(ok).To(BeTrue())
+			Expect(validationError.Errors & jwt.ValidationErrorSignatureInvalid).NotTo(BeZero())
+			Expect(server.ReceivedRequests()).To(HaveLen(0))
+		})
+
+		It("uses the pinned keys and issuer for every identity zone", func() {
+			token, err := makeValidToken(privateKey)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.ForZone(schema.IdentityZone{Id: "zone-a"}).DecodeToken(token, "some.scope")).To(Succeed())
+			Expect(server.ReceivedRequests()).To(HaveLen(0))
+		})
+	})
+
+	Context("with a PEM file", func() {
+		BeforeEach(func() {
+			cfg.VerificationKeysFile = writeKeysFile("key.pem", publicKeyPEM)
+		})
+
+		It("verifies tokens without contacting UAA", func() {
+			token, err := makeValidToken(privateKey)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(client.DecodeToken(token, "some.scope")).To(Succeed())
+			Expect(server.ReceivedRequests()).To(HaveLen(0))
+		})
+
+		Context("when the pinned issuer does not match", func() {
+			BeforeEach(func() {
+				cfg.Issuer = "https://other-uaa.domain.com"
+			})
+
+			It("rejects the token", func() {
+				token, err := makeValidToken(privateKey)
+				Expect(err).NotTo(HaveOccurred())
+
+				err = client.DecodeToken(token, "some.scope")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("invalid issuer"))
+				Expect(server.ReceivedRequests()).To(HaveLen(0))
+			})
+		})
+	})
+
+	It("returns the pinned issuer from FetchIssuer", func() {
+		issuer, err := client.FetchIssuer()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issuer).To(Equal("https://uaa.domain.com"))
+		Expect(server.ReceivedRequests()).To(HaveLen(0))
+	})
+
+	It("fails to create the client without a pinned issuer", func() {
+		cfg.VerificationKeysFile = writeKeysFile("uaa.pem", publicKeyPEM)
+		cfg.Issuer = ""
+		_, err := uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).To(MatchError("Pinned verification keys require a pinned issuer"))
+		Expect(server.ReceivedRequests()).To(HaveLen(0))
+	})
+
+	It("fails to create the client when the keys file cannot be used", func() {
+		cfg.VerificationKeysFile = filepath.Join(keyDir, "missing.pem")
+		_, err := uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("failed read verification keys file"))
+
+		cfg.VerificationKeysFile = writeKeysFile("garbage.pem", []byte("garbage"))
+		_, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).To(MatchError("Verification keys file does not contain any usable keys"))
+	})
+})
@@ -0,0 +1,103 @@
+package uaa_go_client_test
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/config"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("RefreshToken", func() {
+	var (
+		client uaa_go_client.Client
+	)
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			MaxNumberOfRetries:    DefaultMaxNumberOfRetries,
+			RetryInterval:         DefaultRetryInterval,
+			ExpirationBufferInSec: DefaultExpirationBufferTime,
+			RequestTimeout:        DefaultRequestTimeout,
+		}
+		server = ghttp.NewServer()
+
+		url, err := url.Parse(server.URL())
+		Expect(err).ToNot(HaveOccurred())
+		cfg.UaaEndpoint = "http://" + url.Host
+
+		cfg.ClientName = "client-name"
+		cfg.ClientSecret = "client-secret"
+		clock = fakeclock.NewFakeClock(time.Now())
+		logger = lagertest.NewTestLogger("test")
+
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("when UAA returns 200 OK", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/oauth/token"),
+					ghttp.VerifyBasicAuth("client-name", "client-secret"),
+					ghttp.VerifyContentType("application/x-www-form-urlencoded; charset=UTF-8"),
+					verifyBody("grant_type=refresh_token&refresh_token=the-refresh-token"),
+					ghttp.RespondWith(http.StatusOK, `{
+						"access_token": "new access token",
+						"token_type": "bearer",
+						"refresh_token": "new-refresh-token",
+						"expires_in": 43199,
+						"scope": "openid cloud_controller.read",
+						"jti": "the-jti"
+					}`),
+				),
+			)
+		})
+
+		It("returns the full token model", func() {
+			token, err := client.RefreshToken("the-refresh-token")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.ReceivedRequests()).Should(HaveLen(1))
+			Expect(token.AccessToken).To(Equal("new access token"))
+			Expect(token.ExpiresIn).To(Equal(int64(43199)))
+			Expect(token.RefreshToken).To(Equal("new-refresh-token"))
+			Expect(token.TokenType).To(Equal("bearer"))
+			Expect(token.Scope).To(Equal("openid cloud_controller.read"))
+			Expect(token.Jti).To(Equal("the-jti"))
+		})
+	})
+
+	Context("when the refresh token is empty", func() {
+		It("returns an error without contacting UAA", func() {
+			_, err := client.RefreshToken("")
+			Expect(err).To(MatchError("Refresh token cannot be empty"))
+			Expect(server.ReceivedRequests()).To(HaveLen(0))
+		})
+	})
+
+	Context("when UAA rejects the refresh token", func() {
+		It("returns an error and doesn't retry", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusUnauthorized, `{"error":"invalid_token"}`),
+			)
+
+			_, err := client.RefreshToken("expired-refresh-token")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(`status code: 401, body: {"error":"invalid_token"}`))
+			Expect(server.ReceivedRequests()).Should(HaveLen(1))
+		})
+	})
+})
@@ -0,0 +1,110 @@
+package uaa_go_client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"code.cloudfoundry.org/lager"
+	trace "code.cloudfoundry.org/trace-logger"
+)
+
+// UaaError is returned when UAA rejects a revocation or userinfo request.
+type UaaError struct {
+	StatusCode       int
+	ErrorCode        string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+	Body             string `json:"-"`
+}
+
+func (e *UaaError) Error() string {
+	return fmt.Sprintf("status code: %d, body: %s", e.StatusCode, e.Body)
+}
+
+func newUaaError(statusCode int, body []byte) *UaaError {
+	uaaErr := &UaaError{}
+	// UAA usually answers with an OAuth error document, but the body is kept
+	// verbatim for the cases where it does not.
+	_ = json.Unmarshal(body, uaaErr)
+	uaaErr.StatusCode = statusCode
+	uaaErr.Body = string(body)
+	return uaaErr
+}
+
+// RevokeToken revokes a single token by its jti.
+func (u *UaaClient) RevokeToken(tokenId string) error {
+	if tokenId == "" {
+		return errors.New("Token ID cannot be empty")
+	}
+	return u.revoke("DELETE", fmt.Sprintf("/oauth/token/revoke/%s", url.PathEscape(tokenId)))
+}
+
+// RevokeUserTokens revokes every token issued to a user.
+func (u *UaaClient) RevokeUserTokens(userId string) error {
+	if userId == "" {
+		return errors.New("User ID cannot be empty")
+	}
+	return u.revoke("GET", fmt.Sprintf("/oauth/token/revoke/user/%s", url.PathEscape(userId)))
+}
+
+// RevokeClientTokens revokes every token issued to an OAuth client.
+func (u *UaaClient) RevokeClientTokens(clientId string) error {
+	if clientId == "" {
+		return errors.New("OAuth Client ID cannot be empty")
+	}
+	return u.revoke("GET", fmt.Sprintf("/oauth/token/revoke/client/%s", url.PathEscape(clientId)))
+}
+
+// RevokeUserClientTokens revokes every token issued to a user through a
+// specific OAuth client.
+func (u *UaaClient) RevokeUserClientTokens(userId, clientId string) error {
+	if userId == "" {
+		return errors.New("User ID cannot be empty")
+	}
+	if clientId == "" {
+		return errors.New("OAuth Client ID cannot be empty")
+	}
+	return u.revoke("GET", fmt.Sprintf("/oauth/token/revoke/user/%s/client/%s", url.PathEscape(userId), url.PathEscape(clientId)))
+}
+
+func (u *UaaClient) revoke(method, path string) error {
+	logger := u.logger.Session("uaa-client")
+	revokeURL := u.config.UaaEndpoint + path
+	logger.Info("revoke-token-starting", lager.Data{"endpoint": revokeURL})
+
+	token, err := u.FetchToken(false)
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequest(method, revokeURL, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Add("Accept", "application/json; charset=utf-8")
+	request.Header.Add("Authorization", "bearer "+token.AccessToken)
+	trace.DumpRequest(request)
+
+	resp, err := u.do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	trace.DumpResponse(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		uaaErr := newUaaError(resp.StatusCode, body)
+		logger.Error("revoke-token-failed", uaaErr)
+		return uaaErr
+	}
+
+	logger.Info("revoke-token-successful")
+	return nil
+}
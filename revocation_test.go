@@ -0,0 +1,130 @@
+package uaa_go_client_test
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/config"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Token revocation", func() {
+	var (
+		client uaa_go_client.Client
+	)
+
+	verifyRevocation := func(method, path string) http.HandlerFunc {
+		return ghttp.CombineHandlers(
+			ghttp.VerifyRequest(method, path),
+			ghttp.VerifyHeader(http.Header{
+				"Authorization": []string{"bearer admin-token"},
+			}),
+			ghttp.RespondWith(http.StatusOK, `{"status":"ok"}`),
+		)
+	}
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			MaxNumberOfRetries:    DefaultMaxNumberOfRetries,
+			RetryInterval:         DefaultRetryInterval,
+			ExpirationBufferInSec: DefaultExpirationBufferTime,
+			RequestTimeout:        DefaultRequestTimeout,
+		}
+		server = ghttp.NewServer()
+
+		url, err := url.Parse(server.URL())
+		Expect(err).ToNot(HaveOccurred())
+		cfg.UaaEndpoint = "http://" + url.Host
+
+		cfg.ClientName = "client-name"
+		cfg.ClientSecret = "client-secret"
+		clock = fakeclock.NewFakeClock(time.Now())
+		logger = lagertest.NewTestLogger("test")
+
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+
+		server.AppendHandlers(
+			getOauthHandlerFunc(http.StatusOK, &schema.Token{AccessToken: "admin-token", ExpiresIn: 3600}),
+		)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("revokes a single token by id", func() {
+		server.AppendHandlers(verifyRevocation("DELETE", "/oauth/token/revoke/token-jti"))
+		Expect(client.RevokeToken("token-jti")).To(Succeed())
+	})
+
+	It("revokes all tokens of a user", func() {
+		server.AppendHandlers(verifyRevocation("GET", "/oauth/token/revoke/user/user-guid"))
+		Expect(client.RevokeUserTokens("user-guid")).To(Succeed())
+	})
+
+	It("revokes all tokens of a client", func() {
+		server.AppendHandlers(verifyRevocation("GET", "/oauth/token/revoke/client/other-client"))
+		Expect(client.RevokeClientTokens("other-client")).To(Succeed())
+	})
+
+	It("revokes all tokens of a user for a client", func() {
+		server.AppendHandlers(verifyRevocation("GET", "/oauth/token/revoke/user/user-guid/client/other-client"))
+		Expect(client.RevokeUserClientTokens("user-guid", "other-client")).To(Succeed())
+	})
+
+	It("reuses the cached client token", func() {
+		server.AppendHandlers(
+			verifyRevocation("GET", "/oauth/token/revoke/user/user-a"),
+			verifyRevocation("GET", "/oauth/token/revoke/user/user-b"),
+		)
+		Expect(client.RevokeUserTokens("user-a")).To(Succeed())
+		Expect(client.RevokeUserTokens("user-b")).To(Succeed())
+		Expect(server.ReceivedRequests()).To(HaveLen(3))
+	})
+
+	It("returns a typed error when UAA rejects the request", func() {
+		server.AppendHandlers(
+			ghttp.RespondWith(http.StatusForbidden, `{"error":"insufficient_scope","error_description":"Insufficient scope for this resource"}`),
+		)
+
+		err := client.RevokeClientTokens("other-client")
+		Expect(err).To(HaveOccurred())
+		uaaErr, ok := err.(*uaa_go_client.UaaError)
+		Expect(ok).To(BeTrue())
+		Expect(uaaErr.StatusCode).To(Equal(http.StatusForbidden))
+		Expect(uaaErr.ErrorCode).To(Equal("insufficient_scope"))
+		Expect(uaaErr.ErrorDescription).To(Equal("Insufficient scope for this resource"))
+		Expect(err.Error()).To(Equal(`status code: 403, body: {"error":"insufficient_scope","error_description":"Insufficient scope for this resource"}`))
+	})
+
+	It("keeps the body when the error is not an OAuth error document", func() {
+		server.AppendHandlers(
+			ghttp.RespondWith(http.StatusNotFound, "not found"),
+		)
+
+		err := client.RevokeToken("unknown")
+		uaaErr, ok := err.(*uaa_go_client.UaaError)
+		Expect(ok).To(BeTrue())
+		Expect(uaaErr.StatusCode).To(Equal(http.StatusNotFound))
+		Expect(uaaErr.ErrorCode).To(BeEmpty())
+		Expect(uaaErr.Body).To(Equal("not found"))
+	})
+
+	It("validates its arguments", func() {
+		Expect(client.RevokeToken("")).To(MatchError("Token ID cannot be empty"))
+		Expect(client.RevokeUserTokens("")).To(MatchError("User ID cannot be empty"))
+		Expect(client.RevokeClientTokens("")).To(MatchError("OAuth Client ID cannot be empty"))
+		Expect(client.RevokeUserClientTokens("user-guid", "")).To(MatchError("OAuth Client ID cannot be empty"))
+		Expect(server.ReceivedRequests()).To(HaveLen(0))
+	})
+})
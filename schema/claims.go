@@ -0,0 +1,219 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Claims holds the standard and UAA specific claims of a verified access
+// token. Claims that are not modelled here are kept in Extra.
+type Claims struct {
+	Jti       string   `json:"jti,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	Audience  []string `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	Scope     []string `json:"scope,omitempty"`
+	ClientId  string   `json:"client_id,omitempty"`
+	Cid       string   `json:"cid,omitempty"`
+	Azp       string   `json:"azp,omitempty"`
+	GrantType string   `json:"grant_type,omitempty"`
+	UserId    string   `json:"user_id,omitempty"`
+	UserName  string   `json:"user_name,omitempty"`
+	Email     string   `json:"email,omitempty"`
+	Origin    string   `json:"origin,omitempty"`
+	ZoneId    string   `json:"zid,omitempty"`
+	AuthTime  int64    `json:"auth_time,omitempty"`
+	RevSig    string   `json:"rev_sig,omitempty"`
+	Revocable bool     `json:"revocable,omitempty"`
+
+	Extra map[string]interface{} `json:"-"`
+}
+
+var knownClaims = []string{
+	"jti", "sub", "iss", "aud", "exp", "iat", "nbf", "scope", "client_id", "cid", "azp",
+	"grant_type", "user_id", "user_name", "email", "origin", "zid", "auth_time", "rev_sig", "revocable",
+}
+
+// UnmarshalJSON accepts aud as either a string or a list of strings and
+// collects unknown claims into Extra.
+func (c *Claims) UnmarshalJSON(data []byte) error {
+	type claims Claims
+	aux := struct {
+		*claims
+		Audience interface{} `json:"aud,omitempty"`
+	}{claims: (*claims)(c)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	audience, err := audienceClaim(aux.Audience)
+	if err != nil {
+		return err
+	}
+	c.Audience = audience
+
+	c.Extra, err = extraClaims(data, knownClaims)
+	return err
+}
+
+// TokenIntrospection is UAA's answer to whether a token is active. Claims is
+// only set for active tokens.
+type TokenIntrospection struct {
+	Active bool
+	Claims *Claims
+}
+
+func (t *TokenIntrospection) UnmarshalJSON(data []byte) error {
+	status := struct {
+		Active bool `json:"active"`
+	}{}
+	if err := json.Unmarshal(data, &status); err != nil {
+		return err
+	}
+
+	t.Active = status.Active
+	t.Claims = nil
+	if !t.Active {
+		return nil
+	}
+
+	claims := &Claims{}
+	if err := json.Unmarshal(data, claims); err != nil {
+		return err
+	}
+	delete(claims.Extra, "active")
+	if len(claims.Extra) == 0 {
+		claims.Extra = nil
+	}
+	t.Claims = claims
+	return nil
+}
+
+// IDTokenClaims holds the claims of a verified OpenID Connect ID token.
+// Claims that are not modelled here are kept in Extra.
+type IDTokenClaims struct {
+	Subject           string   `json:"sub,omitempty"`
+	Issuer            string   `json:"iss,omitempty"`
+	Audience          []string `json:"aud,omitempty"`
+	ExpiresAt         int64    `json:"exp,omitempty"`
+	IssuedAt          int64    `json:"iat,omitempty"`
+	AuthTime          int64    `json:"auth_time,omitempty"`
+	Nonce             string   `json:"nonce,omitempty"`
+	Azp               string   `json:"azp,omitempty"`
+	AtHash            string   `json:"at_hash,omitempty"`
+	Amr               []string `json:"amr,omitempty"`
+	UserId            string   `json:"user_id,omitempty"`
+	UserName          string   `json:"user_name,omitempty"`
+	Email             string   `json:"email,omitempty"`
+	EmailVerified     bool     `json:"email_verified,omitempty"`
+	GivenName         string   `json:"given_name,omitempty"`
+	FamilyName        string   `json:"family_name,omitempty"`
+	PhoneNumber       string   `json:"phone_number,omitempty"`
+	PreviousLogonTime int64    `json:"previous_logon_time,omitempty"`
+	Origin            string   `json:"origin,omitempty"`
+	ZoneId            string   `json:"zid,omitempty"`
+
+	Extra map[string]interface{} `json:"-"`
+}
+
+var knownIDTokenClaims = []string{
+	"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "azp", "at_hash", "amr", "user_id", "user_name",
+	"email", "email_verified", "given_name", "family_name", "phone_number", "previous_logon_time", "origin", "zid",
+}
+
+// UnmarshalJSON accepts aud as either a string or a list of strings and
+// collects unknown claims into Extra.
+func (c *IDTokenClaims) UnmarshalJSON(data []byte) error {
+	type claims IDTokenClaims
+	aux := struct {
+		*claims
+		Audience interface{} `json:"aud,omitempty"`
+	}{claims: (*claims)(c)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	audience, err := audienceClaim(aux.Audience)
+	if err != nil {
+		return err
+	}
+	c.Audience = audience
+
+	c.Extra, err = extraClaims(data, knownIDTokenClaims)
+	return err
+}
+
+// UserInfo is the profile UAA returns from /userinfo. Attributes that are not
+// modelled here, such as roles and user_attributes, are kept in Extra.
+type UserInfo struct {
+	Subject             string `json:"sub,omitempty"`
+	UserId              string `json:"user_id,omitempty"`
+	UserName            string `json:"user_name,omitempty"`
+	Name                string `json:"name,omitempty"`
+	GivenName           string `json:"given_name,omitempty"`
+	FamilyName          string `json:"family_name,omitempty"`
+	Email               string `json:"email,omitempty"`
+	EmailVerified       bool   `json:"email_verified,omitempty"`
+	PhoneNumber         string `json:"phone_number,omitempty"`
+	PhoneNumberVerified bool   `json:"phone_number_verified,omitempty"`
+	PreviousLogonTime   int64  `json:"previous_logon_time,omitempty"`
+
+	Extra map[string]interface{} `json:"-"`
+}
+
+var knownUserInfoAttributes = []string{
+	"sub", "user_id", "user_name", "name", "given_name", "family_name", "email", "email_verified",
+	"phone_number", "phone_number_verified", "previous_logon_time",
+}
+
+// UnmarshalJSON collects unknown attributes into Extra.
+func (i *UserInfo) UnmarshalJSON(data []byte) error {
+	type userInfo UserInfo
+	if err := json.Unmarshal(data, (*userInfo)(i)); err != nil {
+		return err
+	}
+
+	var err error
+	i.Extra, err = extraClaims(data, knownUserInfoAttributes)
+	return err
+}
+
+func audienceClaim(aud interface{}) ([]string, error) {
+	switch aud := aud.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{aud}, nil
+	case []interface{}:
+		audience := make([]string, 0, len(aud))
+		for _, a := range aud {
+			s, ok := a.(string)
+			if !ok {
+				return nil, errors.New("aud claim must be a string or a list of strings")
+			}
+			audience = append(audience, s)
+		}
+		return audience, nil
+	default:
+		return nil, errors.New("aud claim must be a string or a list of strings")
+	}
+}
+
+func extraClaims(data []byte, known []string) (map[string]interface{}, error) {
+	extra := map[string]interface{}{}
+	if err := json.Unmarshal(data, &extra); err != nil {
+		return nil, err
+	}
+	for _, name := range known {
+		delete(extra, name)
+	}
+	if len(extra) == 0 {
+		return nil, nil
+	}
+	return extra, nil
+}
@@ -1,14 +1,98 @@
 package schema
 
+// Token type identifiers used by OAuth 2.0 token exchange (RFC 8693).
+const (
+	TokenTypeAccessToken  = "urn:ietf:params:oauth:token-type:access_token"
+	TokenTypeRefreshToken = "urn:ietf:params:oauth:token-type:refresh_token"
+	TokenTypeIDToken      = "urn:ietf:params:oauth:token-type:id_token"
+	TokenTypeJWT          = "urn:ietf:params:oauth:token-type:jwt"
+)
+
 type Token struct {
 	AccessToken string `json:"access_token"`
 	// Expire time in seconds
-	ExpiresIn int64 `json:"expires_in"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	// Space separated list of granted scopes
+	Scope     string `json:"scope,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Jti       string `json:"jti,omitempty"`
+	// Set by token exchange to the type of the issued token
+	IssuedTokenType string `json:"issued_token_type,omitempty"`
+	// Set when the openid scope was granted
+	IdToken string `json:"id_token,omitempty"`
+}
+
+// TokenExchangeRequest describes an OAuth 2.0 token exchange (RFC 8693).
+// SubjectTokenType and ActorTokenType default to TokenTypeAccessToken.
+type TokenExchangeRequest struct {
+	SubjectToken       string
+	SubjectTokenType   string
+	ActorToken         string
+	ActorTokenType     string
+	RequestedTokenType string
+	Scopes             []string
+	Audience           []string
+}
+
+// AuthorizationRequest holds the UAA authorize URL a user is sent to along
+// with the values that must be kept to complete the authorization code flow.
+type AuthorizationRequest struct {
+	URL          string
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+// IdentityZone selects a UAA identity zone by id or by subdomain.
+type IdentityZone struct {
+	Id        string
+	Subdomain string
 }
 
 type UaaKey struct {
+	Kid   string `json:"kid,omitempty"`
+	Kty   string `json:"kty,omitempty"`
 	Alg   string `json:"alg"`
+	Use   string `json:"use,omitempty"`
 	Value string `json:"value"`
+	N     string `json:"n,omitempty"`
+	E     string `json:"e,omitempty"`
+	Crv   string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+	Y     string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet is the key set served by UAA's /token_keys endpoint.
+type JSONWebKeySet struct {
+	Keys []UaaKey `json:"keys"`
+}
+
+// OpenIDConfiguration is UAA's OpenID Connect discovery document served at
+// /.well-known/openid-configuration.
+type OpenIDConfiguration struct {
+	Issuer                                string   `json:"issuer"`
+	AuthorizationEndpoint                 string   `json:"authorization_endpoint,omitempty"`
+	TokenEndpoint                         string   `json:"token_endpoint,omitempty"`
+	UserinfoEndpoint                      string   `json:"userinfo_endpoint,omitempty"`
+	JwksUri                               string   `json:"jwks_uri,omitempty"`
+	IntrospectionEndpoint                 string   `json:"introspection_endpoint,omitempty"`
+	RevocationEndpoint                    string   `json:"revocation_endpoint,omitempty"`
+	EndSessionEndpoint                    string   `json:"end_session_endpoint,omitempty"`
+	ScopesSupported                       []string `json:"scopes_supported,omitempty"`
+	ResponseTypesSupported                []string `json:"response_types_supported,omitempty"`
+	GrantTypesSupported                   []string `json:"grant_types_supported,omitempty"`
+	SubjectTypesSupported                 []string `json:"subject_types_supported,omitempty"`
+	TokenEndpointAuthMethodsSupported     []string `json:"token_endpoint_auth_methods_supported,omitempty"`
+	TokenEndpointAuthSigningAlgsSupported []string `json:"token_endpoint_auth_signing_alg_values_supported,omitempty"`
+	IdTokenSigningAlgValuesSupported      []string `json:"id_token_signing_alg_values_supported,omitempty"`
+	IdTokenEncryptionAlgValuesSupported   []string `json:"id_token_encryption_alg_values_supported,omitempty"`
+	ClaimTypesSupported                   []string `json:"claim_types_supported,omitempty"`
+	ClaimsSupported                       []string `json:"claims_supported,omitempty"`
+	ClaimsParameterSupported              bool     `json:"claims_parameter_supported,omitempty"`
+	CodeChallengeMethodsSupported         []string `json:"code_challenge_methods_supported,omitempty"`
+	UILocalesSupported                    []string `json:"ui_locales_supported,omitempty"`
+	ServiceDocumentation                  string   `json:"service_documentation,omitempty"`
 }
 
 type OauthClient struct {
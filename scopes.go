@@ -0,0 +1,102 @@
+package uaa_go_client
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// RequireAnyScope requires the token to be granted at least one of scopes.
+// Scopes may be wildcard patterns, see RequireAllScopes.
+func RequireAnyScope(scopes ...string) VerifyOption {
+	return func(o *verifyOptions) {
+		o.scopeRequirements = append(o.scopeRequirements, func(granted []string) error {
+			for _, scope := range scopes {
+				if hasScope(granted, scope) {
+					return nil
+				}
+			}
+			return errors.New("Token does not have '" + strings.Join(scopes, "', '") + "' scope")
+		})
+	}
+}
+
+// RequireAllScopes requires the token to be granted every one of scopes.
+// A "*" segment in a scope matches exactly one segment of a granted scope,
+// and a trailing "*" matches all remaining segments, so "zones.*.admin"
+// matches "zones.zone-a.admin" and "routing.*" matches "routing.routes.read".
+func RequireAllScopes(scopes ...string) VerifyOption {
+	return func(o *verifyOptions) {
+		o.scopeRequirements = append(o.scopeRequirements, func(granted []string) error {
+			missing := []string{}
+			for _, scope := range scopes {
+				if !hasScope(granted, scope) {
+					missing = append(missing, scope)
+				}
+			}
+			if len(missing) > 0 {
+				return errors.New("Token does not have '" + strings.Join(missing, "', '") + "' scope")
+			}
+			return nil
+		})
+	}
+}
+
+func hasScope(granted []string, pattern string) bool {
+	for _, scope := range granted {
+		if scopeMatches(pattern, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+func scopeMatches(pattern, scope string) bool {
+	if !strings.Contains(pattern, "*") {
+		return pattern == scope
+	}
+
+	patternParts := strings.Split(pattern, ".")
+	scopeParts := strings.Split(scope, ".")
+	for i, part := range patternParts {
+		if part == "*" && i == len(patternParts)-1 {
+			return len(scopeParts) > i
+		}
+		if i >= len(scopeParts) {
+			return false
+		}
+		if part != "*" && part != scopeParts[i] {
+			return false
+		}
+	}
+	return len(patternParts) == len(scopeParts)
+}
+
+// checkScopeClaim makes sure the scope claim is a list of strings, and that
+// it is present when scopes are required.
+func checkScopeClaim(token *jwt.Token, required bool) error {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return errors.New("Invalid token claims")
+	}
+
+	scope, ok := claims["scope"]
+	if !ok || scope == nil {
+		if required {
+			return errors.New("Token does not have a scope claim")
+		}
+		return nil
+	}
+
+	scopes, ok := scope.([]interface{})
+	if !ok {
+		return errors.New("Token scope claim must be a list of strings")
+	}
+	for _, s := range scopes {
+		if _, ok := s.(string); !ok {
+			return errors.New("Token scope claim must be a list of strings")
+		}
+	}
+	return nil
+}
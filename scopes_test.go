@@ -0,0 +1,123 @@
+package uaa_go_client_test
+
+import (
+	"crypto/rsa"
+
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"github.com/golang-jwt/jwt/v4"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scope requirements", func() {
+	var (
+		client     uaa_go_client.Client
+		privateKey *rsa.PrivateKey
+	)
+
+	makeTokenWithScope := func(scope interface{}) string {
+		claims := jwt.MapClaims{
+			"iss": "https://uaa.domain.com",
+			"exp": 2491253686,
+		}
+		if scope != nil {
+			claims["scope"] = scope
+		}
+		return "bearer " + signClaims(jwt.SigningMethodRS256, privateKey, "some-key-id", claims)
+	}
+
+	granted := []string{"routing.routes.read", "routing.router_groups.write", "zones.zone-a.admin", "openid"}
+
+	BeforeEach(func() {
+		startUaaServer()
+
+		var (
+			publicKey *rsa.PublicKey
+			err       error
+		)
+		privateKey, publicKey, err = generateRSAKeyPair()
+		Expect(err).NotTo(HaveOccurred())
+
+		server.AppendHandlers(
+			getJSONWebKeySetFetchHandler(rsaVerificationKey("some-key-id", publicKey)),
+			getIssuerFetchHandler(),
+		)
+
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	verifyScopes := func(option uaa_go_client.VerifyOption) error {
+		_, err := client.VerifyToken(makeTokenWithScope(granted), option)
+		return err
+	}
+
+	Context("any-of", func() {
+		It("passes when one scope is granted", func() {
+			Expect(verifyScopes(uaa_go_client.RequireAnyScope("cloud_controller.read", "openid"))).To(Succeed())
+		})
+
+		It("fails when no scope is granted", func() {
+			err := verifyScopes(uaa_go_client.RequireAnyScope("cloud_controller.read", "uaa.admin"))
+			Expect(err).To(MatchError("Token does not have 'cloud_controller.read', 'uaa.admin' scope"))
+		})
+	})
+
+	Context("all-of", func() {
+		It("passes when every scope is granted", func() {
+			Expect(verifyScopes(uaa_go_client.RequireAllScopes("openid", "routing.routes.read"))).To(Succeed())
+		})
+
+		It("fails listing the missing scopes", func() {
+			err := verifyScopes(uaa_go_client.RequireAllScopes("openid", "uaa.admin", "doppler.firehose"))
+			Expect(err).To(MatchError("Token does not have 'uaa.admin', 'doppler.firehose' scope"))
+		})
+	})
+
+	Context("wildcard patterns", func() {
+		It("matches the remaining segments with a trailing wildcard", func() {
+			Expect(verifyScopes(uaa_go_client.RequireAllScopes("routing.*"))).To(Succeed())
+		})
+
+		It("does not match the prefix alone with a trailing wildcard", func() {
+			err := verifyScopes(uaa_go_client.RequireAllScopes("openid.*"))
+			Expect(err).To(MatchError("Token does not have 'openid.*' scope"))
+		})
+
+		It("matches exactly one segment with an inner wildcard", func() {
+			Expect(verifyScopes(uaa_go_client.RequireAllScopes("zones.*.admin"))).To(Succeed())
+
+			err := verifyScopes(uaa_go_client.RequireAnyScope("routing.*.write.all", "zones.*.read"))
+			Expect(err).To(MatchError("Token does not have 'routing.*.write.all', 'zones.*.read' scope"))
+		})
+	})
+
+	It("combines requirements", func() {
+		_, err := client.VerifyToken(makeTokenWithScope(granted),
+			uaa_go_client.RequireAllScopes("openid"),
+			uaa_go_client.RequireAnyScope("uaa.admin", "zones.*.admin"),
+		)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("returns an error when the scope claim is missing", func() {
+		err := client.DecodeToken(makeTokenWithScope(nil), "openid")
+		Expect(err).To(MatchError("Token does not have a scope claim"))
+	})
+
+	It("returns an error when the scope claim is not a list", func() {
+		err := client.DecodeToken(makeTokenWithScope("openid routing.routes.read"), "openid")
+		Expect(err).To(MatchError("Token scope claim must be a list of strings"))
+	})
+
+	It("returns an error when the scope claim contains non-strings", func() {
+		_, err := client.VerifyToken(makeTokenWithScope([]interface{}{"openid", 42}))
+		Expect(err).To(MatchError("Token scope claim must be a list of strings"))
+	})
+})
@@ -0,0 +1,148 @@
+package uaa_go_client_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+	"github.com/golang-jwt/jwt/v4"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Allowed signing algorithms", func() {
+	var (
+		client uaa_go_client.Client
+	)
+
+	signToken := func(method jwt.SigningMethod, key interface{}) string {
+		return "bearer " + signClaims(method, key, "some-key-id", tokenClaims())
+	}
+
+	serveKeys := func(keys ...schema.UaaKey) {
+		server.AppendHandlers(getJSONWebKeySetFetchHandler(keys...), getIssuerFetchHandler())
+	}
+
+	ecJSONWebKey := func(publicKey *ecdsa.PublicKey, crv string) schema.UaaKey {
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		return schema.UaaKey{
+			Kid: "some-key-id",
+			Kty: "EC",
+			Crv: crv,
+			X:   base64.RawURLEncoding.EncodeToString(publicKey.X.FillBytes(make([]byte, size))),
+			Y:   base64.RawURLEncoding.EncodeToString(publicKey.Y.FillBytes(make([]byte, size))),
+		}
+	}
+
+	BeforeEach(func() {
+		startUaaServer()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("with the default allow-list", func() {
+		It("rejects RSASSA-PSS signed tokens", func() {
+			privateKey, publicKey, err := generateRSAKeyPair()
+			Expect(err).NotTo(HaveOccurred())
+			publicKeyPEM, err := publicKeyToPEM(publicKey)
+			Expect(err).NotTo(HaveOccurred())
+			serveKeys(schema.UaaKey{Kid: "some-key-id", Alg: "PS256", Value: string(publicKeyPEM)})
+
+			token := signToken(jwt.SigningMethodPS256, privateKey)
+			Expect(client.DecodeToken(token, "some.scope")).To(MatchError("invalid signing method"))
+		})
+	})
+
+	Context("when RSASSA-PSS is allowed", func() {
+		BeforeEach(func() {
+			cfg.AllowedSigningAlgorithms = []string{"PS256", "PS512"}
+		})
+
+		It("verifies RSASSA-PSS signed tokens", func() {
+			privateKey, publicKey, err := generateRSAKeyPair()
+			Expect(err).NotTo(HaveOccurred())
+			publicKeyPEM, err := publicKeyToPEM(publicKey)
+			Expect(err).NotTo(HaveOccurred())
+			serveKeys(schema.UaaKey{Kid: "some-key-id", Alg: "PS256", Value: string(publicKeyPEM)})
+
+			Expect(client.DecodeToken(signToken(jwt.SigningMethodPS256, privateKey), "some.scope")).To(Succeed())
+		})
+
+		It("rejects PKCS#1 v1.5 signed tokens", func() {
+			privateKey, publicKey, err := generateRSAKeyPair()
+			Expect(err).NotTo(HaveOccurred())
+			publicKeyPEM, err := publicKeyToPEM(publicKey)
+			Expect(err).NotTo(HaveOccurred())
+			serveKeys(schema.UaaKey{Kid: "some-key-id", Alg: "RS256", Value: string(publicKeyPEM)})
+
+			token, err := makeValidToken(privateKey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(client.DecodeToken(token, "some.scope")).To(MatchError("invalid signing method"))
+		})
+	})
+
+	Context("when ECDSA is allowed", func() {
+		var privateKey *ecdsa.PrivateKey
+
+		BeforeEach(func() {
+			cfg.AllowedSigningAlgorithms = []string{"ES256", "ES384"}
+
+			var err error
+			privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("verifies tokens with an EC key from the key set", func() {
+			serveKeys(ecJSONWebKey(&privateKey.PublicKey, "P-256"))
+
+			Expect(client.DecodeToken(signToken(jwt.SigningMethodES256, privateKey), "some.scope")).To(Succeed())
+		})
+
+		It("verifies tokens with a PEM encoded EC key", func() {
+			keyBytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+			Expect(err).NotTo(HaveOccurred())
+			publicKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: keyBytes})
+			serveKeys(schema.UaaKey{Kid: "some-key-id", Alg: "ES256", Value: string(publicKeyPEM)})
+
+			Expect(client.DecodeToken(signToken(jwt.SigningMethodES256, privateKey), "some.scope")).To(Succeed())
+		})
+
+		It("rejects tokens whose algorithm does not match the key curve", func() {
+			otherKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+			Expect(err).NotTo(HaveOccurred())
+			serveKeys(ecJSONWebKey(&privateKey.PublicKey, "P-256"))
+
+			Expect(client.DecodeToken(signToken(jwt.SigningMethodES384, otherKey), "some.scope")).To(MatchError("invalid signing method"))
+		})
+
+		It("skips EC keys on unsupported curves", func() {
+			badKey := ecJSONWebKey(&privateKey.PublicKey, "P-256")
+			badKey.Crv = "secp256k1"
+			server.AppendHandlers(getJSONWebKeySetFetchHandler(badKey))
+
+			_, err := client.FetchKeySet()
+			Expect(err).To(MatchError("UAA did not return any usable verification keys"))
+		})
+	})
+
+	It("fails to create a client with an unsupported algorithm", func() {
+		cfg.AllowedSigningAlgorithms = []string{"RS256", "none"}
+		_, err := uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).To(MatchError("Unsupported signing algorithm: none"))
+	})
+})
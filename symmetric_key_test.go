@@ -0,0 +1,141 @@
+package uaa_go_client_test
+
+import (
+	"net/http"
+
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/config"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Symmetric signing keys", func() {
+	const sharedSecret = "shared-signing-secret"
+
+	var (
+		client uaa_go_client.Client
+	)
+
+	symmetricKey := schema.UaaKey{Kid: "some-key-id", Kty: "MAC", Alg: "HS256", Value: sharedSecret}
+
+	BeforeEach(func() {
+		startUaaServer()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("when symmetric signing keys are allowed", func() {
+		BeforeEach(func() {
+			cfg.AllowSymmetricSigningKeys = true
+		})
+
+		It("fetches the key set as the client and verifies HMAC signed tokens", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyBasicAuth("client-name", "client-secret"),
+					getJSONWebKeySetFetchHandler(symmetricKey),
+				),
+				getIssuerFetchHandler(),
+			)
+
+			token, err := makeSpoofedToken([]byte(sharedSecret))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(client.DecodeToken(token, "some.scope")).To(Succeed())
+		})
+
+		It("fetches the legacy key as the client", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", TokenKeyEndpoint),
+					ghttp.VerifyBasicAuth("client-name", "client-secret"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, schema.UaaKey{Alg: "HS256", Value: sharedSecret}),
+				),
+			)
+
+			key, err := client.FetchKey()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key).To(Equal(sharedSecret))
+		})
+
+		It("rejects HMAC signed tokens when UAA publishes an RSA key", func() {
+			_, publicKey, err := generateRSAKeyPair()
+			Expect(err).NotTo(HaveOccurred())
+			rsaKey := rsaVerificationKey("some-key-id", publicKey)
+
+			server.AppendHandlers(
+				getJSONWebKeySetFetchHandler(rsaKey),
+				getIssuerFetchHandler(),
+			)
+
+			token, err := makeSpoofedToken([]byte(rsaKey.Value))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(client.DecodeToken(token, "some.scope")).To(MatchError("invalid signing method"))
+		})
+
+		It("rejects RSA signed tokens when UAA publishes a symmetric key", func() {
+			privateKey, _, err := generateRSAKeyPair()
+			Expect(err).NotTo(HaveOccurred())
+
+			server.AppendHandlers(
+				getJSONWebKeySetFetchHandler(symmetricKey),
+				getIssuerFetchHandler(),
+			)
+
+			token, err := makeValidToken(privateKey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(client.DecodeToken(token, "some.scope")).To(MatchError("invalid signing method"))
+		})
+
+		It("fails to create a client that does not authenticate with a secret", func() {
+			cfg.ClientAuthMethod = config.ClientAuthPrivateKeyJWT
+
+			_, err := uaa_go_client.NewClient(logger, cfg, clock)
+			Expect(err).To(MatchError("Symmetric signing keys require client_secret_basic client authentication"))
+		})
+
+		It("requires client credentials to fetch keys", func() {
+			cfg.ClientSecret = ""
+
+			_, err := client.FetchKeySet()
+			Expect(err).To(MatchError("OAuth Client Secret cannot be empty"))
+			Expect(server.ReceivedRequests()).To(HaveLen(0))
+		})
+	})
+
+	Context("when symmetric signing keys are not allowed", func() {
+		It("fetches keys anonymously and ignores symmetric keys", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					func(w http.ResponseWriter, r *http.Request) {
+						Expect(r.Header.Get("Authorization")).To(BeEmpty())
+					},
+					getJSONWebKeySetFetchHandler(symmetricKey),
+				),
+			)
+
+			_, err := client.FetchKeySet()
+			Expect(err).To(MatchError("UAA did not return any usable verification keys"))
+		})
+
+		It("rejects HMAC signed tokens", func() {
+			token, err := makeSpoofedToken([]byte(sharedSecret))
+			Expect(err).NotTo(HaveOccurred())
+
+			server.AppendHandlers(getJSONWebKeySetFetchHandler(symmetricKey))
+
+			Expect(client.DecodeToken(token, "some.scope")).To(MatchError("UAA did not return any usable verification keys"))
+		})
+	})
+})
@@ -0,0 +1,138 @@
+package uaa_go_client_test
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/config"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("UAA specific grants", func() {
+	var (
+		client uaa_go_client.Client
+	)
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			MaxNumberOfRetries:    DefaultMaxNumberOfRetries,
+			RetryInterval:         DefaultRetryInterval,
+			ExpirationBufferInSec: DefaultExpirationBufferTime,
+			RequestTimeout:        DefaultRequestTimeout,
+		}
+		server = ghttp.NewServer()
+
+		url, err := url.Parse(server.URL())
+		Expect(err).ToNot(HaveOccurred())
+		cfg.UaaEndpoint = "http://" + url.Host
+
+		cfg.ClientName = "client-name"
+		cfg.ClientSecret = "client-secret"
+		clock = fakeclock.NewFakeClock(time.Now())
+		logger = lagertest.NewTestLogger("test")
+
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("FetchPasscodeToken", func() {
+		It("performs a password grant with the passcode", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/oauth/token"),
+					ghttp.VerifyBasicAuth("client-name", "client-secret"),
+					ghttp.VerifyContentType("application/x-www-form-urlencoded; charset=UTF-8"),
+					verifyBody("grant_type=password&passcode=Xy12ab&scope=openid"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, &schema.Token{AccessToken: "the sso user token"}),
+				),
+			)
+
+			token, err := client.FetchPasscodeToken("Xy12ab", uaa_go_client.WithScopes("openid"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.AccessToken).To(Equal("the sso user token"))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("returns an error when the passcode is empty", func() {
+			_, err := client.FetchPasscodeToken("")
+			Expect(err).To(MatchError("Passcode cannot be empty"))
+			Expect(server.ReceivedRequests()).To(HaveLen(0))
+		})
+
+		It("returns an error when UAA rejects the passcode", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusUnauthorized, "invalid passcode"),
+			)
+
+			_, err := client.FetchPasscodeToken("used")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("status code: 401, body: invalid passcode"))
+		})
+	})
+
+	Describe("FetchUserTokenForClient", func() {
+		It("authenticates with the user token and requests a token for the target client", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/oauth/token"),
+					ghttp.VerifyHeader(http.Header{
+						"Authorization": []string{"bearer the-user-token"},
+						"Accept":        []string{"application/json; charset=utf-8"},
+					}),
+					ghttp.VerifyContentType("application/x-www-form-urlencoded; charset=UTF-8"),
+					verifyBody("client_id=other-client&grant_type=user_token&response_type=token&scope=cloud_controller.read"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, &schema.Token{
+						RefreshToken: "refresh token for other client",
+					}),
+				),
+			)
+
+			token, err := client.FetchUserTokenForClient("the-user-token", "other-client", "cloud_controller.read")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.RefreshToken).To(Equal("refresh token for other client"))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("does not require client credentials", func() {
+			cfg.ClientSecret = ""
+			server.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusOK, &schema.Token{RefreshToken: "refresh token"}),
+			)
+
+			_, err := client.FetchUserTokenForClient("the-user-token", "other-client")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns an error when the user token or target client is missing", func() {
+			_, err := client.FetchUserTokenForClient("", "other-client")
+			Expect(err).To(MatchError("User access token cannot be empty"))
+
+			_, err = client.FetchUserTokenForClient("the-user-token", "")
+			Expect(err).To(MatchError("Target OAuth Client ID cannot be empty"))
+			Expect(server.ReceivedRequests()).To(HaveLen(0))
+		})
+
+		It("returns an error when UAA rejects the user token", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusForbidden, "insufficient scope"),
+			)
+
+			_, err := client.FetchUserTokenForClient("the-user-token", "other-client")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("status code: 403, body: insufficient scope"))
+		})
+	})
+})
@@ -0,0 +1,61 @@
+package uaa_go_client
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"code.cloudfoundry.org/lager"
+	trace "code.cloudfoundry.org/trace-logger"
+
+	"code.cloudfoundry.org/uaa-go-client/schema"
+)
+
+// FetchUserInfo returns the profile of the user an access token was issued
+// to. The token must have been granted the openid scope.
+func (u *UaaClient) FetchUserInfo(accessToken string) (*schema.UserInfo, error) {
+	logger := u.logger.Session("uaa-client")
+
+	if accessToken == "" {
+		return nil, errors.New("Access token cannot be empty")
+	}
+
+	userInfoURL := u.userInfoURL(logger)
+	logger.Debug("fetch-user-info-starting", lager.Data{"endpoint": userInfoURL})
+
+	request, err := http.NewRequest("GET", userInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Add("Accept", "application/json; charset=utf-8")
+	request.Header.Add("Authorization", "bearer "+accessToken)
+	trace.DumpRequest(request)
+
+	resp, err := u.do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	trace.DumpResponse(resp)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		uaaErr := newUaaError(resp.StatusCode, body)
+		logger.Error("fetch-user-info-failed", uaaErr)
+		return nil, uaaErr
+	}
+
+	userInfo := &schema.UserInfo{}
+	err = json.Unmarshal(body, userInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("fetch-user-info-successful")
+	return userInfo, nil
+}
@@ -0,0 +1,144 @@
+package uaa_go_client_test
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"code.cloudfoundry.org/lager/lagertest"
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/config"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("FetchUserInfo", func() {
+	var (
+		client uaa_go_client.Client
+	)
+
+	BeforeEach(func() {
+		cfg = &config.Config{
+			MaxNumberOfRetries:    DefaultMaxNumberOfRetries,
+			RetryInterval:         DefaultRetryInterval,
+			ExpirationBufferInSec: DefaultExpirationBufferTime,
+			RequestTimeout:        DefaultRequestTimeout,
+		}
+		server = ghttp.NewServer()
+
+		url, err := url.Parse(server.URL())
+		Expect(err).ToNot(HaveOccurred())
+		cfg.UaaEndpoint = "http://" + url.Host
+
+		cfg.ClientName = "client-name"
+		cfg.ClientSecret = "client-secret"
+		clock = fakeclock.NewFakeClock(time.Now())
+		logger = lagertest.NewTestLogger("test")
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("returns the profile of the token's user", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/userinfo"),
+				ghttp.VerifyHeader(http.Header{
+					"Authorization": []string{"bearer the-user-token"},
+					"Accept":        []string{"application/json; charset=utf-8"},
+				}),
+				ghttp.RespondWith(http.StatusOK, `{
+					"sub": "user-guid",
+					"user_id": "user-guid",
+					"user_name": "marissa",
+					"name": "Marissa Bloggs",
+					"given_name": "Marissa",
+					"family_name": "Bloggs",
+					"email": "marissa@example.com",
+					"email_verified": true,
+					"phone_number": "555-1234",
+					"phone_number_verified": false,
+					"previous_logon_time": 1481253000000,
+					"roles": ["admin"],
+					"user_attributes": {"cost_center": ["0815"]}
+				}`),
+			),
+		)
+
+		userInfo, err := client.FetchUserInfo("the-user-token")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(userInfo).To(Equal(&schema.UserInfo{
+			Subject:           "user-guid",
+			UserId:            "user-guid",
+			UserName:          "marissa",
+			Name:              "Marissa Bloggs",
+			GivenName:         "Marissa",
+			FamilyName:        "Bloggs",
+			Email:             "marissa@example.com",
+			EmailVerified:     true,
+			PhoneNumber:       "555-1234",
+			PreviousLogonTime: 1481253000000,
+			Extra: map[string]interface{}{
+				"roles":           []interface{}{"admin"},
+				"user_attributes": map[string]interface{}{"cost_center": []interface{}{"0815"}},
+			},
+		}))
+	})
+
+	It("returns the UAA error when the token is rejected", func() {
+		server.AppendHandlers(
+			ghttp.RespondWith(http.StatusUnauthorized, `{"error":"invalid_token","error_description":"Invalid access token"}`),
+		)
+
+		_, err := client.FetchUserInfo("expired-token")
+		uaaErr, ok := err.(*uaa_go_client.UaaError)
+		Expect(ok).To(BeTrue())
+		Expect(uaaErr.StatusCode).To(Equal(http.StatusUnauthorized))
+		Expect(uaaErr.ErrorCode).To(Equal("invalid_token"))
+		Expect(err.Error()).To(Equal(`status code: 401, body: {"error":"invalid_token","error_description":"Invalid access token"}`))
+	})
+
+	It("requires an access token", func() {
+		_, err := client.FetchUserInfo("")
+		Expect(err).To(MatchError("Access token cannot be empty"))
+		Expect(server.ReceivedRequests()).To(HaveLen(0))
+	})
+
+	Context("when discovered endpoints are used", func() {
+		BeforeEach(func() {
+			cfg.UseDiscoveredEndpoints = true
+		})
+
+		It("fetches the profile from the advertised endpoint", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", OpenIDConfigEndpoint),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, schema.OpenIDConfiguration{
+						Issuer:           "https://uaa.domain.com/oauth/token",
+						UserinfoEndpoint: server.URL() + "/uaa/userinfo",
+					}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/uaa/userinfo"),
+					ghttp.RespondWith(http.StatusOK, `{"user_id":"user-guid"}`),
+				),
+			)
+
+			userInfo, err := client.FetchUserInfo("the-user-token")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(userInfo.UserId).To(Equal("user-guid"))
+		})
+	})
+})
@@ -0,0 +1,187 @@
+package uaa_go_client
+
+import (
+	"encoding/json"
+	"errors"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/golang-jwt/jwt/v4"
+
+	"code.cloudfoundry.org/uaa-go-client/schema"
+)
+
+// VerifyOption adds a requirement that a token must meet in VerifyToken.
+type VerifyOption func(*verifyOptions)
+
+type verifyOptions struct {
+	scopeRequirements []func(granted []string) error
+	introspect        bool
+}
+
+func (u *UaaClient) VerifyToken(uaaToken string, opts ...VerifyOption) (*schema.Claims, error) {
+	logger := u.logger.Session("uaa-client")
+	logger.Debug("decode-token-started")
+	defer logger.Debug("decode-token-completed")
+	var err error
+	jwtToken, err := checkTokenFormat(uaaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &verifyOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	token, err := u.verifySignedToken(logger, jwtToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if !u.isValidAudience(token) {
+		logger.Info("decode-token-invalid-audience", lager.Data{"audiences": u.config.Audiences})
+		return nil, ErrInvalidAudience
+	}
+
+	err = checkScopeClaim(token, len(options.scopeRequirements) > 0)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := typedClaims(token)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, requirement := range options.scopeRequirements {
+		if err := requirement(claims.Scope); err != nil {
+			return nil, err
+		}
+	}
+
+	if options.introspect {
+		introspection, err := u.IntrospectToken(jwtToken)
+		if err != nil {
+			return nil, err
+		}
+		if !introspection.Active {
+			logger.Info("decode-token-inactive")
+			return nil, ErrTokenInactive
+		}
+	}
+
+	return claims, nil
+}
+
+// verifySignedToken checks the signature, signing method, issuer and time
+// based claims of a JWT, refetching the verification keys once when the
+// token was signed with a key the client does not know yet.
+func (u *UaaClient) verifySignedToken(logger lager.Logger, jwtToken string) (*jwt.Token, error) {
+	var (
+		token            *jwt.Token
+		keys             keySet
+		err              error
+		forceUaaKeyFetch bool
+	)
+
+	// Time based claims are validated below against the client clock with
+	// the configured leeway.
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+
+	for i := 0; i < 2; i++ {
+		keys, err = u.getVerificationKeys(logger, forceUaaKeyFetch)
+
+		if err == nil {
+			token, err = parser.Parse(jwtToken, func(t *jwt.Token) (interface{}, error) {
+				if !u.isValidSigningMethod(t) {
+					return nil, errors.New("invalid signing method")
+				}
+				if !u.isValidIssuer(t) {
+					return nil, errors.New("invalid issuer")
+				}
+
+				kid, _ := t.Header["kid"].(string)
+				key, err := keys.lookup(kid)
+				if err != nil {
+					return nil, err
+				}
+				if !key.supports(t.Method) {
+					return nil, errors.New("invalid signing method")
+				}
+
+				return key.key, nil
+			})
+
+			if err != nil {
+				logger.Error("decode-token-failed", err)
+				refetch := matchesError(err, jwt.ValidationErrorSignatureInvalid) || errors.Is(err, errNoMatchingKey)
+				if refetch && u.pinnedKeys == nil {
+					forceUaaKeyFetch = true
+					continue
+				}
+			}
+		}
+
+		break
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.validateTimeClaims(token)
+	if err != nil {
+		logger.Error("decode-token-failed", err)
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// validateTimeClaims checks exp, nbf and iat, allowing for the configured
+// clock skew between UAA and this client.
+func (u *UaaClient) validateTimeClaims(token *jwt.Token) error {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil
+	}
+
+	now := u.clock.Now()
+	leeway := u.config.ClockSkewLeeway
+	if leeway < 0 {
+		leeway = 0
+	}
+
+	vErr := new(jwt.ValidationError)
+	if !claims.VerifyExpiresAt(now.Add(-leeway).Unix(), false) {
+		vErr.Inner = errors.New("Token is expired")
+		vErr.Errors |= jwt.ValidationErrorExpired
+	}
+	if !claims.VerifyIssuedAt(now.Add(leeway).Unix(), false) {
+		vErr.Inner = errors.New("Token used before issued")
+		vErr.Errors |= jwt.ValidationErrorIssuedAt
+	}
+	if !claims.VerifyNotBefore(now.Add(leeway).Unix(), false) {
+		vErr.Inner = errors.New("Token is not valid yet")
+		vErr.Errors |= jwt.ValidationErrorNotValidYet
+	}
+
+	if vErr.Errors == 0 {
+		return nil
+	}
+	return vErr
+}
+
+func typedClaims(token *jwt.Token) (*schema.Claims, error) {
+	data, err := json.Marshal(token.Claims)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &schema.Claims{}
+	err = json.Unmarshal(data, claims)
+	if err != nil {
+		return nil, errors.New("Invalid token claims: " + err.Error())
+	}
+	return claims, nil
+}
@@ -0,0 +1,135 @@
+package uaa_go_client_test
+
+import (
+	"crypto/rsa"
+
+	uaa_go_client "code.cloudfoundry.org/uaa-go-client"
+	"code.cloudfoundry.org/uaa-go-client/schema"
+	"github.com/golang-jwt/jwt/v4"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("VerifyToken", func() {
+	var (
+		client     uaa_go_client.Client
+		privateKey *rsa.PrivateKey
+	)
+
+	bearerToken := func(claims jwt.MapClaims) string {
+		return "bearer " + signClaims(jwt.SigningMethodRS256, privateKey, "some-key-id", claims)
+	}
+
+	BeforeEach(func() {
+		startUaaServer()
+
+		var (
+			publicKey *rsa.PublicKey
+			err       error
+		)
+		privateKey, publicKey, err = generateRSAKeyPair()
+		Expect(err).NotTo(HaveOccurred())
+
+		server.AppendHandlers(
+			getJSONWebKeySetFetchHandler(rsaVerificationKey("some-key-id", publicKey)),
+			getIssuerFetchHandler(),
+		)
+
+		client, err = uaa_go_client.NewClient(logger, cfg, clock)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client).NotTo(BeNil())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("returns the standard and UAA specific claims", func() {
+		token := bearerToken(jwt.MapClaims{
+			"jti":        "token-id",
+			"sub":        "user-guid",
+			"iss":        "https://uaa.domain.com",
+			"aud":        []string{"routing", "openid"},
+			"exp":        2491253686,
+			"iat":        1481253086,
+			"scope":      []string{"routing.routes.read", "openid"},
+			"client_id":  "cf",
+			"cid":        "cf",
+			"azp":        "cf",
+			"grant_type": "password",
+			"user_id":    "user-guid",
+			"user_name":  "marissa",
+			"email":      "marissa@example.com",
+			"origin":     "uaa",
+			"zid":        "uaa",
+			"auth_time":  1481253000,
+			"rev_sig":    "1e0b9c0d",
+			"revocable":  true,
+		})
+
+		claims, err := client.VerifyToken(token, uaa_go_client.RequireAnyScope("routing.routes.read"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(claims).To(Equal(&schema.Claims{
+			Jti:       "token-id",
+			Subject:   "user-guid",
+			Issuer:    "https://uaa.domain.com",
+			Audience:  []string{"routing", "openid"},
+			ExpiresAt: 2491253686,
+			IssuedAt:  1481253086,
+			Scope:     []string{"routing.routes.read", "openid"},
+			ClientId:  "cf",
+			Cid:       "cf",
+			Azp:       "cf",
+			GrantType: "password",
+			UserId:    "user-guid",
+			UserName:  "marissa",
+			Email:     "marissa@example.com",
+			Origin:    "uaa",
+			ZoneId:    "uaa",
+			AuthTime:  1481253000,
+			RevSig:    "1e0b9c0d",
+			Revocable: true,
+		}))
+	})
+
+	It("keeps unknown claims in Extra and accepts a single audience", func() {
+		token := bearerToken(jwt.MapClaims{
+			"iss":      "https://uaa.domain.com",
+			"aud":      "routing",
+			"exp":      2491253686,
+			"scope":    []string{"some.scope"},
+			"ext_attr": map[string]interface{}{"enhancer": "custom"},
+		})
+
+		claims, err := client.VerifyToken(token)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(claims.Audience).To(Equal([]string{"routing"}))
+		Expect(claims.Extra).To(Equal(map[string]interface{}{
+			"ext_attr": map[string]interface{}{"enhancer": "custom"},
+		}))
+	})
+
+	It("does not require scopes unless asked to", func() {
+		token := bearerToken(jwt.MapClaims{
+			"iss": "https://uaa.domain.com",
+			"exp": 2491253686,
+		})
+
+		claims, err := client.VerifyToken(token)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(claims.Scope).To(BeEmpty())
+	})
+
+	It("returns an error when no required scope is granted", func() {
+		token := bearerToken(jwt.MapClaims{
+			"iss":   "https://uaa.domain.com",
+			"exp":   2491253686,
+			"scope": []string{"some.scope"},
+		})
+
+		claims, err := client.VerifyToken(token, uaa_go_client.RequireAnyScope("other.scope", "another.scope"))
+		Expect(err).To(MatchError("Token does not have 'other.scope', 'another.scope' scope"))
+		Expect(claims).To(BeNil())
+	})
+})